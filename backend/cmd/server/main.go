@@ -1,8 +1,10 @@
 package main
 
 import (
+	"backend/internal/controllers/finmath/calendar"
 	"backend/internal/router"
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,18 @@ func init() {
 }
 
 func main() {
+	// Extra holiday calendars (.json or .ics) can be dropped into CALENDAR_DIR
+	if dir := os.Getenv("CALENDAR_DIR"); dir != "" {
+		loaded, err := calendar.LoadDir(dir)
+		// Loading stops at the first bad file; anything before it stays registered
+		if err != nil {
+			log.Println("Failed to load calendars:", err)
+		}
+		if len(loaded) > 0 {
+			log.Println("Loaded calendars:", loaded)
+		}
+	}
+
 	r := router.SetupRouter()
 	log.Println("Starting server on :8000")
 	if err := r.Run(":8000"); err != nil {
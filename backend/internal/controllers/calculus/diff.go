@@ -4,26 +4,16 @@ import (
 	"errors"
 )
 
-func NumericalDerivative(x, h float64) (float64, error) {
+func NumericalDerivative(f func(float64) float64, x, h float64) (float64, error) {
 	if h <= 0 {
 		h = 1e-8
 	}
 
-	// Example function: f(x) = x^2 + 2x + 1
-	f := func(x float64) float64 {
-		return x*x + 2*x + 1
-	}
-
 	// Central difference method
 	return (f(x+h) - f(x-h)) / (2 * h), nil
 }
 
-func PartialDerivative(x, y, h float64, variable string) (float64, error) {
-	// Example function: f(x,y) = x^2 + y^2 + xy
-	f := func(x, y float64) float64 {
-		return x*x + y*y + x*y
-	}
-
+func PartialDerivative(f func(x, y float64) float64, x, y, h float64, variable string) (float64, error) {
 	if h <= 0 {
 		h = 1e-8
 	}
@@ -38,7 +28,7 @@ func PartialDerivative(x, y, h float64, variable string) (float64, error) {
 	}
 }
 
-func TrapezoidalRule(a, b float64, n int) (float64, error) {
+func TrapezoidalRule(f func(float64) float64, a, b float64, n int) (float64, error) {
 	if n <= 0 {
 		return 0, errors.New("number of intervals must be positive")
 	}
@@ -47,11 +37,6 @@ func TrapezoidalRule(a, b float64, n int) (float64, error) {
 		return 0, errors.New("upper bound must be greater than lower bound")
 	}
 
-	// Example function: f(x) = x^2
-	f := func(x float64) float64 {
-		return x * x
-	}
-
 	h := (b - a) / float64(n)
 	sum := f(a) + f(b)
 
@@ -63,7 +48,7 @@ func TrapezoidalRule(a, b float64, n int) (float64, error) {
 	return h * sum / 2, nil
 }
 
-func SimpsonsRule(a, b float64, n int) (float64, error) {
+func SimpsonsRule(f func(float64) float64, a, b float64, n int) (float64, error) {
 	if n <= 0 || n%2 != 0 {
 		return 0, errors.New("number of intervals must be positive and even")
 	}
@@ -72,11 +57,6 @@ func SimpsonsRule(a, b float64, n int) (float64, error) {
 		return 0, errors.New("upper bound must be greater than lower bound")
 	}
 
-	// Example function: f(x) = x^2
-	f := func(x float64) float64 {
-		return x * x
-	}
-
 	h := (b - a) / float64(n)
 	sum := f(a) + f(b)
 
@@ -92,12 +72,7 @@ func SimpsonsRule(a, b float64, n int) (float64, error) {
 	return h * sum / 3, nil
 }
 
-func Gradient(x, y, h float64) ([]float64, error) {
-	// Example function: f(x,y) = x^2 + y^2 + xy
-	f := func(x, y float64) float64 {
-		return x*x + y*y + x*y
-	}
-
+func Gradient(f func(x, y float64) float64, x, y, h float64) ([]float64, error) {
 	if h <= 0 {
 		h = 1e-8
 	}
@@ -0,0 +1,153 @@
+package dist
+
+import (
+	"math"
+)
+
+// Gauss-Legendre half-nodes and weights used by the Genz bivariate normal algorithm
+var (
+	bvnWeights6 = []float64{0.1713244923791705, 0.3607615730481384, 0.4679139345726904}
+	bvnNodes6   = []float64{0.9324695142031522, 0.6612093864662647, 0.2386191860831970}
+
+	bvnWeights12 = []float64{
+		0.04717533638651177, 0.1069393259953183, 0.1600783285433464,
+		0.2031674267230659, 0.2334925365383547, 0.2491470458134029,
+	}
+	bvnNodes12 = []float64{
+		0.9815606342467191, 0.9041172563704750, 0.7699026741943050,
+		0.5873179542866171, 0.3678314989981802, 0.1252334085114692,
+	}
+
+	bvnWeights20 = []float64{
+		0.01761400713915212, 0.04060142980038694, 0.06267204833410906,
+		0.08327674157670475, 0.1019301198172404, 0.1181945319615184,
+		0.1316886384491766, 0.1420961093183821, 0.1491729864726037,
+		0.1527533871307259,
+	}
+	bvnNodes20 = []float64{
+		0.9931285991850949, 0.9639719272779138, 0.9122344282513259,
+		0.8391169718222188, 0.7463319064601508, 0.6360536807265150,
+		0.5108670019508271, 0.3737060887154196, 0.2277858511416451,
+		0.07652652113349733,
+	}
+)
+
+// BivariateNormalCDF returns P(X <= a, Y <= b) for standard normals with correlation rho
+func BivariateNormalCDF(a, b, rho float64) float64 {
+	if rho < -1 || rho > 1 || math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return bivariateNormalUpper(-a, -b, rho)
+}
+
+// Genz (2004) algorithm for the upper tail P(X > h, Y > k)
+func bivariateNormalUpper(h, k, r float64) float64 {
+	switch {
+	case math.IsInf(h, 1) || math.IsInf(k, 1):
+		return 0
+	case math.IsInf(h, -1):
+		if math.IsInf(k, -1) {
+			return 1
+		}
+		return StandardNormalCDF(-k)
+	case math.IsInf(k, -1):
+		return StandardNormalCDF(-h)
+	case r == 0:
+		return StandardNormalCDF(-h) * StandardNormalCDF(-k)
+	}
+
+	var w, x []float64
+	switch {
+	case math.Abs(r) < 0.3:
+		w, x = bvnWeights6, bvnNodes6
+	case math.Abs(r) < 0.75:
+		w, x = bvnWeights12, bvnNodes12
+	default:
+		w, x = bvnWeights20, bvnNodes20
+	}
+
+	// Mirror the half-nodes onto [0, 2]
+	nodes := make([]float64, 0, 2*len(x))
+	weights := make([]float64, 0, 2*len(w))
+	for i := range x {
+		nodes = append(nodes, 1-x[i])
+		weights = append(weights, w[i])
+	}
+	for i := range x {
+		nodes = append(nodes, 1+x[i])
+		weights = append(weights, w[i])
+	}
+
+	tp := 2 * math.Pi
+	hk := h * k
+	bvn := 0.0
+
+	if math.Abs(r) < 0.925 {
+		hs := (h*h + k*k) / 2
+		asr := math.Asin(r) / 2
+		for i, xi := range nodes {
+			sn := math.Sin(asr * xi)
+			bvn += weights[i] * math.Exp((sn*hk-hs)/(1-sn*sn))
+		}
+		bvn = bvn*asr/tp + StandardNormalCDF(-h)*StandardNormalCDF(-k)
+		return clampProbability(bvn)
+	}
+
+	if r < 0 {
+		k = -k
+		hk = -hk
+	}
+
+	if math.Abs(r) < 1 {
+		as := 1 - r*r
+		a := math.Sqrt(as)
+		bs := (h - k) * (h - k)
+		asr := -(bs/as + hk) / 2
+		c := (4 - hk) / 8
+		d := (12 - hk) / 80
+
+		if asr > -100 {
+			bvn = a * math.Exp(asr) * (1 - c*(bs-as)*(1-d*bs)/3 + c*d*as*as)
+		}
+		if hk > -100 {
+			b := math.Sqrt(bs)
+			sp := math.Sqrt(tp) * StandardNormalCDF(-b/a)
+			bvn -= math.Exp(-hk/2) * sp * b * (1 - c*bs*(1-d*bs)/3)
+		}
+
+		a /= 2
+		sum := 0.0
+		for i, xi := range nodes {
+			xs := (a * xi) * (a * xi)
+			asr := -(bs/xs + hk) / 2
+			if asr <= -100 {
+				continue
+			}
+			sp := 1 + c*xs*(1+5*d*xs)
+			rs := math.Sqrt(1 - xs)
+			ep := math.Exp(-(hk/2)*xs/((1+rs)*(1+rs))) / rs
+			sum += weights[i] * math.Exp(asr) * (sp - ep)
+		}
+		bvn = (a*sum - bvn) / tp
+	}
+
+	if r > 0 {
+		bvn += StandardNormalCDF(-math.Max(h, k))
+	} else if h >= k {
+		bvn = -bvn
+	} else {
+		var l float64
+		if h < 0 {
+			l = StandardNormalCDF(k) - StandardNormalCDF(h)
+		} else {
+			l = StandardNormalCDF(-h) - StandardNormalCDF(-k)
+		}
+		bvn = l - bvn
+	}
+
+	return clampProbability(bvn)
+}
+
+func clampProbability(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
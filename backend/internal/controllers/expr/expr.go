@@ -0,0 +1,238 @@
+// Package expr parses and evaluates arithmetic and boolean expressions over
+// named variables, such as "pe < 15 AND roe > 0.12" or "sqrt(x^2 + y^2)".
+// Comparisons and logical operators yield 1 for true and 0 for false, and any
+// non-zero value counts as true.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	maxLength = 2000
+	maxDepth  = 100
+)
+
+// MissingVariableError reports a variable with no value during evaluation
+type MissingVariableError struct {
+	Name string
+}
+
+func (e *MissingVariableError) Error() string { return "unknown variable: " + e.Name }
+
+// Lookup resolves a variable, reporting false when it has no value
+type Lookup func(name string) (float64, bool)
+
+type Expr struct {
+	source string
+	root   node
+}
+
+func (e *Expr) String() string { return e.source }
+
+// Parse compiles an expression once so it can be evaluated many times
+func Parse(src string) (*Expr, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("expression is empty")
+	}
+	if len(src) > maxLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxLength)
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return &Expr{source: src, root: root}, nil
+}
+
+func (e *Expr) Eval(lookup Lookup) (float64, error) {
+	return e.root.eval(lookup)
+}
+
+// EvalMap evaluates with variables taken from vars
+func (e *Expr) EvalMap(vars map[string]float64) (float64, error) {
+	return e.Eval(func(name string) (float64, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+// Func binds the expression to the named variables, which take the arguments
+// in order. It fails when the expression refers to any other variable.
+func (e *Expr) Func(names ...string) (func(args ...float64) float64, error) {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	for _, name := range e.Variables() {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("unknown variable %s; use %s", name, strings.Join(names, ", "))
+		}
+	}
+	return func(args ...float64) float64 {
+		// Every variable is bound, so evaluation cannot fail
+		v, _ := e.Eval(func(name string) (float64, bool) {
+			return args[index[name]], true
+		})
+		return v
+	}, nil
+}
+
+// Variables lists the distinct variable names the expression refers to
+func (e *Expr) Variables() []string {
+	seen := map[string]bool{}
+	e.root.variables(seen)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func truth(v float64) bool { return v != 0 && !math.IsNaN(v) }
+
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type node interface {
+	eval(lookup Lookup) (float64, error)
+	variables(seen map[string]bool)
+}
+
+type number float64
+
+func (n number) eval(Lookup) (float64, error) { return float64(n), nil }
+func (n number) variables(map[string]bool)    {}
+
+type variable string
+
+func (v variable) eval(lookup Lookup) (float64, error) {
+	if lookup != nil {
+		if value, ok := lookup(string(v)); ok {
+			return value, nil
+		}
+	}
+	return 0, &MissingVariableError{Name: string(v)}
+}
+
+func (v variable) variables(seen map[string]bool) { seen[string(v)] = true }
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (u unary) eval(lookup Lookup) (float64, error) {
+	v, err := u.operand.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	if u.op == "!" {
+		return boolean(!truth(v)), nil
+	}
+	return -v, nil
+}
+
+func (u unary) variables(seen map[string]bool) { u.operand.variables(seen) }
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b binary) eval(lookup Lookup) (float64, error) {
+	l, err := b.left.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	// Logical operators short-circuit, so the right side may reference
+	// variables that are only present when the left side holds
+	switch b.op {
+	case "&&":
+		if !truth(l) {
+			return 0, nil
+		}
+	case "||":
+		if truth(l) {
+			return 1, nil
+		}
+	}
+	r, err := b.right.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case "&&", "||":
+		return boolean(truth(r)), nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		return l / r, nil
+	case "%":
+		return math.Mod(l, r), nil
+	case "^":
+		return math.Pow(l, r), nil
+	case "<":
+		return boolean(l < r), nil
+	case "<=":
+		return boolean(l <= r), nil
+	case ">":
+		return boolean(l > r), nil
+	case ">=":
+		return boolean(l >= r), nil
+	case "==":
+		return boolean(l == r), nil
+	case "!=":
+		return boolean(l != r), nil
+	}
+	return 0, errors.New("unknown operator " + b.op)
+}
+
+func (b binary) variables(seen map[string]bool) {
+	b.left.variables(seen)
+	b.right.variables(seen)
+}
+
+type call struct {
+	fn   function
+	args []node
+}
+
+func (c call) eval(lookup Lookup) (float64, error) {
+	values := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		v, err := arg.eval(lookup)
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+	return c.fn.apply(values), nil
+}
+
+func (c call) variables(seen map[string]bool) {
+	for _, arg := range c.args {
+		arg.variables(seen)
+	}
+}
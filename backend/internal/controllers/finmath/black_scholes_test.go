@@ -0,0 +1,73 @@
+package finmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlackScholes(t *testing.T) {
+	tests := []struct {
+		name          string
+		S, K, T, r, v float64
+		call, put     float64
+	}{
+		// Hull, Options, Futures and Other Derivatives, example 15.6
+		{"hull", 42, 40, 0.5, 0.1, 0.2, 4.7594, 0.8086},
+		// Haug, The Complete Guide to Option Pricing Formulas, 1.1.1
+		{"haug", 60, 65, 0.25, 0.08, 0.3, 2.1334, 5.8463},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, put := BlackScholes(tt.S, tt.K, tt.T, tt.r, tt.v)
+			if math.Abs(call-tt.call) > 1e-4 || math.Abs(put-tt.put) > 1e-4 {
+				t.Errorf("got call %.4f put %.4f, want %.4f and %.4f", call, put, tt.call, tt.put)
+			}
+		})
+	}
+}
+
+func TestImpliedVolatilityRoundTrip(t *testing.T) {
+	tests := []struct {
+		K, T, v float64
+		isCall  bool
+	}{
+		{80, 0.25, 0.15, false},
+		{100, 1, 0.2, true},
+		{120, 2, 0.35, true},
+		{100, 0.05, 0.6, false},
+	}
+	for _, tt := range tests {
+		call, put := BlackScholes(100, tt.K, tt.T, 0.03, tt.v)
+		price := put
+		if tt.isCall {
+			price = call
+		}
+		got, err := ImpliedVolatility(100, tt.K, tt.T, 0.03, price, tt.isCall)
+		if err != nil || math.Abs(got-tt.v) > 1e-6 {
+			t.Errorf("K=%g T=%g: got %v (%v), want %g", tt.K, tt.T, got, err, tt.v)
+		}
+	}
+}
+
+func TestGreeksMatchFiniteDifferences(t *testing.T) {
+	S, K, T, r, v := 100.0, 105.0, 0.75, 0.04, 0.25
+	g := Greeks(S, K, T, r, v)
+	h := 1e-4
+
+	up, _ := BlackScholes(S+h, K, T, r, v)
+	down, _ := BlackScholes(S-h, K, T, r, v)
+	mid, _ := BlackScholes(S, K, T, r, v)
+	volUp, _ := BlackScholes(S, K, T, r, v+h)
+	volDown, _ := BlackScholes(S, K, T, r, v-h)
+
+	checks := map[string]float64{
+		"call_delta": (up - down) / (2 * h),
+		"gamma":      (up - 2*mid + down) / (h * h),
+		"vega":       (volUp - volDown) / (2 * h) / 100, // Per vol point
+	}
+	for name, want := range checks {
+		if math.Abs(g[name]-want) > 1e-4*math.Max(1, math.Abs(want)) {
+			t.Errorf("%s = %.6f, finite difference %.6f", name, g[name], want)
+		}
+	}
+}
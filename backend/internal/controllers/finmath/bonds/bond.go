@@ -0,0 +1,275 @@
+package bonds
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Bond is a fixed-coupon bullet bond. Zero-coupon bonds use a zero CouponRate and
+// are compounded at Frequency like any other bond. Calendar is only needed for
+// BUS/252 accrual; nil means weekends only.
+type Bond struct {
+	Maturity   time.Time
+	CouponRate float64
+	Frequency  int
+	Face       float64
+	DayCount   calendar.DayCount
+	Calendar   *calendar.Calendar
+}
+
+type Cashflow struct {
+	Date   string  `json:"date"`
+	Time   float64 `json:"time"` // Years from settlement, ACT/365F
+	Amount float64 `json:"amount"`
+}
+
+type Analytics struct {
+	CleanPrice       float64    `json:"clean_price"`
+	DirtyPrice       float64    `json:"dirty_price"`
+	AccruedInterest  float64    `json:"accrued_interest"`
+	Yield            float64    `json:"yield"`
+	MacaulayDuration float64    `json:"macaulay_duration"`
+	ModifiedDuration float64    `json:"modified_duration"`
+	Convexity        float64    `json:"convexity"`
+	DV01             float64    `json:"dv01"`
+	PreviousCoupon   string     `json:"previous_coupon"`
+	NextCoupon       string     `json:"next_coupon"`
+	Cashflows        []Cashflow `json:"cashflows"`
+}
+
+// RateCurve supplies continuously compounded zero rates by time in years
+type RateCurve interface {
+	ZeroRate(t float64) float64
+}
+
+// ZeroCurve is a continuously compounded zero curve, linearly interpolated in time
+// and flat beyond its end points
+type ZeroCurve struct {
+	Tenors []float64 `json:"tenors"`
+	Rates  []float64 `json:"rates"`
+}
+
+const dateLayout = "2006-01-02"
+
+type schedule struct {
+	previous time.Time
+	coupons  []time.Time // Remaining coupon dates, the last one is maturity
+	elapsed  float64     // Fraction of the current coupon period already accrued
+}
+
+func (b Bond) Validate() error {
+	switch b.Frequency {
+	case 1, 2, 4, 12:
+	default:
+		return errors.New("frequency must be 1, 2, 4 or 12 coupons per year")
+	}
+	if b.CouponRate < 0 {
+		return errors.New("coupon rate cannot be negative")
+	}
+	if b.Face <= 0 {
+		return errors.New("face value must be positive")
+	}
+	if _, err := calendar.ParseDayCount(string(b.DayCount)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// schedule rolls coupon dates back from maturity until it passes settlement
+func (b Bond) schedule(settlement time.Time) (schedule, error) {
+	if !settlement.Before(b.Maturity) {
+		return schedule{}, errors.New("settlement must be before maturity")
+	}
+
+	months := 12 / b.Frequency
+	eom := calendar.IsMonthEnd(b.Maturity)
+
+	var coupons []time.Time
+	date := b.Maturity
+	for n := 1; date.After(settlement); n++ {
+		coupons = append(coupons, date)
+		date = calendar.AddMonths(b.Maturity, -n*months, eom)
+	}
+	sort.Slice(coupons, func(i, j int) bool { return coupons[i].Before(coupons[j]) })
+
+	return schedule{
+		previous: date,
+		coupons:  coupons,
+		elapsed:  b.DayCount.AccrualFraction(date, settlement, coupons[0], b.Frequency, b.Calendar),
+	}, nil
+}
+
+func (b Bond) coupon() float64 {
+	return b.Face * b.CouponRate / float64(b.Frequency)
+}
+
+// dirtyPrice discounts the remaining cashflows at the periodically compounded yield
+func (b Bond) dirtyPrice(s schedule, y float64) float64 {
+	f := float64(b.Frequency)
+	base := 1 + y/f
+	w := 1 - s.elapsed
+
+	price := 0.0
+	for i := range s.coupons {
+		cf := b.coupon()
+		if i == len(s.coupons)-1 {
+			cf += b.Face
+		}
+		price += cf / math.Pow(base, float64(i)+w)
+	}
+	return price
+}
+
+func (b Bond) analytics(settlement time.Time, s schedule, y float64) Analytics {
+	f := float64(b.Frequency)
+	base := 1 + y/f
+	w := 1 - s.elapsed
+	accrued := b.coupon() * s.elapsed
+
+	dirty, macaulay, convexity := 0.0, 0.0, 0.0
+	cashflows := make([]Cashflow, len(s.coupons))
+	for i, date := range s.coupons {
+		cf := b.coupon()
+		if i == len(s.coupons)-1 {
+			cf += b.Face
+		}
+		periods := float64(i) + w
+		pv := cf / math.Pow(base, periods)
+
+		dirty += pv
+		macaulay += periods / f * pv
+		convexity += periods * (periods + 1) / (f * f) * pv
+
+		cashflows[i] = Cashflow{
+			Date:   date.Format(dateLayout),
+			Time:   date.Sub(settlement).Hours() / 24 / 365,
+			Amount: cf,
+		}
+	}
+
+	macaulay /= dirty
+	modified := macaulay / base
+	convexity /= dirty * base * base
+
+	return Analytics{
+		CleanPrice:       dirty - accrued,
+		DirtyPrice:       dirty,
+		AccruedInterest:  accrued,
+		Yield:            y,
+		MacaulayDuration: macaulay,
+		ModifiedDuration: modified,
+		Convexity:        convexity,
+		DV01:             modified * dirty * 1e-4,
+		PreviousCoupon:   s.previous.Format(dateLayout),
+		NextCoupon:       s.coupons[0].Format(dateLayout),
+		Cashflows:        cashflows,
+	}
+}
+
+// PriceFromYield returns prices and risk measures for a yield quoted with the
+// bond's own compounding frequency
+func (b Bond) PriceFromYield(settlement time.Time, y float64) (Analytics, error) {
+	if err := b.Validate(); err != nil {
+		return Analytics{}, err
+	}
+	if y <= -float64(b.Frequency) {
+		return Analytics{}, errors.New("yield is below the compounding limit")
+	}
+
+	s, err := b.schedule(settlement)
+	if err != nil {
+		return Analytics{}, err
+	}
+	return b.analytics(settlement, s, y), nil
+}
+
+// YieldFromPrice solves for the yield to maturity matching a clean or dirty price
+func (b Bond) YieldFromPrice(settlement time.Time, price float64, isClean bool) (Analytics, error) {
+	if err := b.Validate(); err != nil {
+		return Analytics{}, err
+	}
+	if price <= 0 {
+		return Analytics{}, errors.New("price must be positive")
+	}
+
+	s, err := b.schedule(settlement)
+	if err != nil {
+		return Analytics{}, err
+	}
+
+	target := price
+	if isClean {
+		target += b.coupon() * s.elapsed
+	}
+
+	// Price is decreasing in yield; search just above the compounding limit
+	lower := -float64(b.Frequency) + 1e-6
+	upper := 1.0
+	for b.dirtyPrice(s, upper) > target {
+		upper *= 2
+		if upper > 1e4 {
+			return Analytics{}, errors.New("no yield matches the price")
+		}
+	}
+
+	y, err := opt.BrentRoot(func(y float64) float64 {
+		return b.dirtyPrice(s, y) - target
+	}, lower, upper, 1e-12)
+	if err != nil {
+		return Analytics{}, err
+	}
+
+	return b.analytics(settlement, s, y), nil
+}
+
+func (c ZeroCurve) Validate() error {
+	if len(c.Tenors) == 0 || len(c.Tenors) != len(c.Rates) {
+		return errors.New("zero curve needs matching, non-empty tenors and rates")
+	}
+	if !sort.Float64sAreSorted(c.Tenors) {
+		return errors.New("zero curve tenors must be increasing")
+	}
+	return nil
+}
+
+func (c ZeroCurve) ZeroRate(t float64) float64 {
+	n := len(c.Tenors)
+	if t <= c.Tenors[0] {
+		return c.Rates[0]
+	}
+	if t >= c.Tenors[n-1] {
+		return c.Rates[n-1]
+	}
+	i := sort.SearchFloat64s(c.Tenors, t)
+	w := (t - c.Tenors[i-1]) / (c.Tenors[i] - c.Tenors[i-1])
+	return c.Rates[i-1] + w*(c.Rates[i]-c.Rates[i-1])
+}
+
+// ZSpread finds the constant spread over the zero curve that reprices the bond
+func (b Bond) ZSpread(settlement time.Time, price float64, isClean bool, curve RateCurve) (float64, error) {
+	a, err := b.YieldFromPrice(settlement, price, isClean)
+	if err != nil {
+		return 0, err
+	}
+
+	pv := func(spread float64) float64 {
+		sum := 0.0
+		for _, cf := range a.Cashflows {
+			sum += cf.Amount * math.Exp(-(curve.ZeroRate(cf.Time)+spread)*cf.Time)
+		}
+		return sum - a.DirtyPrice
+	}
+
+	lo, hi := -0.5, 0.5
+	for pv(lo)*pv(hi) > 0 {
+		lo, hi = lo*2, hi*2
+		if hi > 100 {
+			return 0, errors.New("failed to bracket z-spread")
+		}
+	}
+	return opt.BrentRoot(pv, lo, hi, 1e-12)
+}
@@ -0,0 +1,100 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BusinessDayConvention string
+
+const (
+	Unadjusted        BusinessDayConvention = "unadjusted"
+	Following         BusinessDayConvention = "following"
+	ModifiedFollowing BusinessDayConvention = "modified_following"
+	Preceding         BusinessDayConvention = "preceding"
+	ModifiedPreceding BusinessDayConvention = "modified_preceding"
+)
+
+// ParseConvention defaults to modified following
+func ParseConvention(s string) (BusinessDayConvention, error) {
+	switch bdc := BusinessDayConvention(strings.ToLower(strings.TrimSpace(s))); bdc {
+	case Unadjusted, Following, ModifiedFollowing, Preceding, ModifiedPreceding:
+		return bdc, nil
+	case "":
+		return ModifiedFollowing, nil
+	}
+	return "", errors.New("unsupported business day convention: " + s)
+}
+
+// maxRollDays bounds the search for a business day, so a calendar whose
+// holidays cover a whole stretch of dates cannot stall a roll
+const maxRollDays = 366
+
+func (c *Calendar) roll(t time.Time, step int) (time.Time, error) {
+	d := Date(t)
+	for i := 0; !c.IsBusinessDay(d); i++ {
+		if i == maxRollDays {
+			return time.Time{}, fmt.Errorf("no business day within %d days of %s", maxRollDays, Date(t).Format("2006-01-02"))
+		}
+		d = d.AddDate(0, 0, step)
+	}
+	return d, nil
+}
+
+// Adjust moves t onto a business day. The modified conventions roll the other way
+// when the plain roll would leave the month.
+func (c *Calendar) Adjust(t time.Time, bdc BusinessDayConvention) (time.Time, error) {
+	switch bdc {
+	case Unadjusted:
+		return Date(t), nil
+	case Following:
+		return c.roll(t, 1)
+	case Preceding:
+		return c.roll(t, -1)
+	case ModifiedPreceding:
+		if d, err := c.roll(t, -1); err != nil || d.Month() == t.Month() {
+			return d, err
+		}
+		return c.roll(t, 1)
+	default:
+		if d, err := c.roll(t, 1); err != nil || d.Month() == t.Month() {
+			return d, err
+		}
+		return c.roll(t, -1)
+	}
+}
+
+// AddBusinessDays moves n business days forward, or backward when n is negative
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	d := Date(t)
+	for n > 0 {
+		next, err := c.roll(d.AddDate(0, 0, step), step)
+		if err != nil {
+			return time.Time{}, err
+		}
+		d = next
+		n--
+	}
+	return d, nil
+}
+
+// BusinessDaysBetween counts business days in (start, end], negative if end is
+// before start
+func (c *Calendar) BusinessDaysBetween(start, end time.Time) int {
+	if end.Before(start) {
+		return -c.BusinessDaysBetween(end, start)
+	}
+	n := 0
+	for d := Date(start).AddDate(0, 0, 1); !d.After(Date(end)); d = d.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(d) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,235 @@
+package calendar
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Calendar decides which dates are business days. Holidays come from fixed dates
+// and, for the built-in markets, from rules evaluated per year.
+type Calendar struct {
+	Name    string
+	weekend map[time.Weekday]bool
+	fixed   map[time.Time]bool
+	rules   func(year int) []time.Time
+
+	mu    sync.Mutex
+	years map[int]map[time.Time]bool
+}
+
+func newCalendar(name string, rules func(year int) []time.Time) *Calendar {
+	return &Calendar{
+		Name:    name,
+		weekend: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		fixed:   map[time.Time]bool{},
+		rules:   rules,
+		years:   map[int]map[time.Time]bool{},
+	}
+}
+
+// Date truncates t to midnight UTC on its calendar date
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func ymd(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// AddMonths moves t by n months, clamping to month end. With endOfMonth set, the
+// result is always the last day of its month.
+func AddMonths(t time.Time, n int, endOfMonth bool) time.Time {
+	first := ymd(t.Year(), t.Month(), 1).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if endOfMonth || day > last {
+		day = last
+	}
+	return ymd(first.Year(), first.Month(), day)
+}
+
+func IsMonthEnd(t time.Time) bool {
+	return t.AddDate(0, 0, 1).Day() == 1
+}
+
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	if c == nil {
+		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	}
+	return c.weekend[t.Weekday()]
+}
+
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	d := Date(t)
+	if c.fixed[d] {
+		return true
+	}
+	if c.rules == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	holidays, ok := c.years[d.Year()]
+	if !ok {
+		holidays = map[time.Time]bool{}
+		for _, h := range c.rules(d.Year()) {
+			holidays[h] = true
+		}
+		c.years[d.Year()] = holidays
+	}
+	return holidays[d]
+}
+
+// IsBusinessDay treats a nil calendar as weekends-only
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return !c.IsWeekend(t) && !c.IsHoliday(t)
+}
+
+// Holidays lists the weekday holidays in [start, end]
+func (c *Calendar) Holidays(start, end time.Time) []time.Time {
+	var out []time.Time
+	for d := Date(start); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !c.IsWeekend(d) && c.IsHoliday(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// AddHolidays marks extra dates as holidays
+func (c *Calendar) AddHolidays(dates ...time.Time) {
+	for _, d := range dates {
+		c.fixed[Date(d)] = true
+	}
+}
+
+// easterSunday uses the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return ymd(year, time.Month(month), day)
+}
+
+// nthWeekday returns the nth weekday of a month; n = -1 picks the last one
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := ymd(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := ymd(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// observedUS moves Saturday holidays to Friday and Sunday holidays to Monday
+func observedUS(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// substituteUK moves weekend holidays to the next weekday not already a holiday
+func substituteUK(days ...time.Time) []time.Time {
+	taken := map[time.Time]bool{}
+	for _, d := range days {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			taken[d] = true
+		}
+	}
+	for _, d := range days {
+		if !taken[d] {
+			for d.Weekday() == time.Saturday || d.Weekday() == time.Sunday || taken[d] {
+				d = d.AddDate(0, 0, 1)
+			}
+			taken[d] = true
+		}
+	}
+
+	out := make([]time.Time, 0, len(taken))
+	for d := range taken {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+// NYSE follows the exchange's regular holiday rules. One-off closures such as
+// national days of mourning are not included.
+func NYSE() *Calendar {
+	return newCalendar("NYSE", func(year int) []time.Time {
+		easter := easterSunday(year)
+		days := []time.Time{
+			nthWeekday(year, time.January, time.Monday, 3),  // Martin Luther King Jr. Day
+			nthWeekday(year, time.February, time.Monday, 3), // Washington's Birthday
+			easter.AddDate(0, 0, -2),                        // Good Friday
+			nthWeekday(year, time.May, time.Monday, -1),     // Memorial Day
+			observedUS(ymd(year, time.July, 4)),
+			nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+			nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving
+			observedUS(ymd(year, time.December, 25)),
+		}
+		// A Saturday New Year's Day is not moved back into December
+		if newYear := ymd(year, time.January, 1); newYear.Weekday() != time.Saturday {
+			days = append(days, observedUS(newYear))
+		}
+		if year >= 2022 {
+			days = append(days, observedUS(ymd(year, time.June, 19)))
+		}
+		return days
+	})
+}
+
+// TARGET is the euro area's TARGET2 settlement calendar
+func TARGET() *Calendar {
+	return newCalendar("TARGET", func(year int) []time.Time {
+		easter := easterSunday(year)
+		return []time.Time{
+			ymd(year, time.January, 1),
+			easter.AddDate(0, 0, -2),
+			easter.AddDate(0, 0, 1),
+			ymd(year, time.May, 1),
+			ymd(year, time.December, 25),
+			ymd(year, time.December, 26),
+		}
+	})
+}
+
+// UK is the England and Wales bank holiday calendar. Early May, spring and
+// summer holidays follow the usual Monday rules; one-off changes are not included.
+func UK() *Calendar {
+	return newCalendar("UK", func(year int) []time.Time {
+		easter := easterSunday(year)
+		days := substituteUK(ymd(year, time.January, 1))
+		days = append(days, substituteUK(ymd(year, time.December, 25), ymd(year, time.December, 26))...)
+		return append(days,
+			easter.AddDate(0, 0, -2),
+			easter.AddDate(0, 0, 1),
+			nthWeekday(year, time.May, time.Monday, 1),
+			nthWeekday(year, time.May, time.Monday, -1),
+			nthWeekday(year, time.August, time.Monday, -1),
+		)
+	})
+}
+
+// Weekends has no holidays besides Saturdays and Sundays
+func Weekends() *Calendar {
+	return newCalendar("WEEKENDS", nil)
+}
@@ -0,0 +1,221 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCalendars caps the registry, built-in calendars included
+const maxCalendars = 100
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Calendar{}
+	builtin    = map[string]bool{}
+)
+
+func init() {
+	for _, c := range []*Calendar{NYSE(), TARGET(), UK(), Weekends()} {
+		name := strings.ToUpper(c.Name)
+		registry[name] = c
+		builtin[name] = true
+	}
+}
+
+// Register adds or replaces a calendar under its upper-cased name. The built-in
+// calendars cannot be replaced, and a calendar must keep at least one weekday.
+func Register(c *Calendar) error {
+	if len(c.weekend) >= 7 {
+		return errors.New("calendar " + c.Name + " must have at least one weekday")
+	}
+	name := strings.ToUpper(strings.TrimSpace(c.Name))
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if builtin[name] {
+		return errors.New("built-in calendar " + name + " cannot be replaced; load it under another name or extend it with base")
+	}
+	if _, ok := registry[name]; !ok && len(registry) >= maxCalendars {
+		return errors.New("calendar registry is full")
+	}
+	registry[name] = c
+	return nil
+}
+
+// Lookup finds a registered calendar. An empty name returns nil, which every
+// Calendar method treats as weekends only.
+func Lookup(name string) (*Calendar, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := registry[strings.ToUpper(strings.TrimSpace(name))]
+	if !ok {
+		return nil, errors.New("unknown calendar: " + name)
+	}
+	return c, nil
+}
+
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// calendarFile is the JSON layout: holidays as YYYY-MM-DD, an optional weekend
+// list and an optional registered calendar to extend
+type calendarFile struct {
+	Name     string   `json:"name"`
+	Base     string   `json:"base"`
+	Weekend  []string `json:"weekend"`
+	Holidays []string `json:"holidays"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+func ParseJSON(data []byte) (*Calendar, error) {
+	var file calendarFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(file.Name) == "" {
+		return nil, errors.New("calendar name is required")
+	}
+
+	c := newCalendar(file.Name, nil)
+	if file.Base != "" {
+		base, err := Lookup(file.Base)
+		if err != nil {
+			return nil, err
+		}
+		c.rules = base.rules
+		c.weekend = base.weekend
+		for d := range base.fixed {
+			c.fixed[d] = true
+		}
+	}
+
+	if len(file.Weekend) > 0 {
+		c.weekend = map[time.Weekday]bool{}
+		for _, name := range file.Weekend {
+			day, ok := weekdays[strings.ToLower(name)]
+			if !ok {
+				return nil, errors.New("unknown weekday: " + name)
+			}
+			c.weekend[day] = true
+		}
+	}
+
+	for _, s := range file.Holidays {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: dates must be YYYY-MM-DD", s)
+		}
+		c.AddHolidays(d)
+	}
+	return c, nil
+}
+
+// ParseICS reads the DTSTART of every VEVENT as an all-day holiday. The calendar
+// takes X-WR-CALNAME when present and name otherwise.
+func ParseICS(name string, data []byte) (*Calendar, error) {
+	// Undo RFC 5545 line folding before scanning
+	unfolded := bytes.ReplaceAll(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte("\n "), nil)
+
+	c := newCalendar(name, nil)
+	scanner := bufio.NewScanner(bytes.NewReader(unfolded))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		property, _, _ := strings.Cut(key, ";")
+
+		switch strings.ToUpper(property) {
+		case "X-WR-CALNAME":
+			c.Name = strings.TrimSpace(value)
+		case "DTSTART":
+			if len(value) < 8 {
+				return nil, fmt.Errorf("invalid DTSTART %q", value)
+			}
+			d, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q", value)
+			}
+			c.AddHolidays(d)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		return nil, errors.New("calendar name is required")
+	}
+	return c, nil
+}
+
+// LoadFile parses a .json or .ics calendar and registers it. ICS calendars
+// without X-WR-CALNAME are named after the file.
+func LoadFile(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *Calendar
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		c, err = ParseJSON(data)
+	case ".ics":
+		c, err = ParseICS(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), data)
+	default:
+		return nil, errors.New("calendar files must be .json or .ics")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := Register(c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// LoadDir registers every .json and .ics calendar in dir
+func LoadDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".ics") {
+			continue
+		}
+		c, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return loaded, err
+		}
+		loaded = append(loaded, c.Name)
+	}
+	return loaded, nil
+}
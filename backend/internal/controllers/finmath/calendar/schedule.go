@@ -0,0 +1,120 @@
+package calendar
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type StubType string
+
+const (
+	ShortFront StubType = "short_front"
+	LongFront  StubType = "long_front"
+	ShortBack  StubType = "short_back"
+	LongBack   StubType = "long_back"
+)
+
+func ParseStub(s string) (StubType, error) {
+	switch stub := StubType(strings.ToLower(strings.TrimSpace(s))); stub {
+	case ShortFront, LongFront, ShortBack, LongBack:
+		return stub, nil
+	case "":
+		return ShortFront, nil
+	}
+	return "", errors.New("stub must be short_front, long_front, short_back or long_back")
+}
+
+type Period struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	AdjustedStart time.Time `json:"adjusted_start"`
+	AdjustedEnd   time.Time `json:"adjusted_end"`
+	IsStub        bool      `json:"is_stub"`
+}
+
+// GenerateSchedule builds regular periods of 12/frequency months between start and
+// end. Front stubs roll dates back from end, back stubs roll forward from start; the
+// long variants merge an irregular stub into its neighbouring period. With endOfMonth
+// set, roll dates stick to month end when the anchor date is a month end.
+func GenerateSchedule(start, end time.Time, frequency int, stub StubType, bdc BusinessDayConvention, cal *Calendar, endOfMonth bool) ([]Period, error) {
+	start, end = Date(start), Date(end)
+	if !start.Before(end) {
+		return nil, errors.New("schedule start must be before its end")
+	}
+	switch frequency {
+	case 1, 2, 3, 4, 6, 12:
+	default:
+		return nil, errors.New("frequency must be 1, 2, 3, 4, 6 or 12 periods per year")
+	}
+
+	months := 12 / frequency
+	backward := stub == ShortFront || stub == LongFront
+
+	anchor := start
+	if backward {
+		anchor = end
+	}
+	eom := endOfMonth && IsMonthEnd(anchor)
+
+	// Roll dates strictly inside (start, end), in generation order
+	var rolls []time.Time
+	for n := 1; ; n++ {
+		step := n * months
+		if backward {
+			step = -step
+		}
+		d := AddMonths(anchor, step, eom)
+		if !d.After(start) || !d.Before(end) {
+			break
+		}
+		rolls = append(rolls, d)
+	}
+
+	var irregular bool
+	if backward {
+		irregular = !AddMonths(anchor, -(len(rolls)+1)*months, eom).Equal(start)
+		for i, j := 0, len(rolls)-1; i < j; i, j = i+1, j-1 {
+			rolls[i], rolls[j] = rolls[j], rolls[i]
+		}
+	} else {
+		irregular = !AddMonths(anchor, (len(rolls)+1)*months, eom).Equal(end)
+	}
+
+	// A long stub absorbs the adjacent regular period
+	if irregular && len(rolls) > 0 {
+		switch stub {
+		case LongFront:
+			rolls = rolls[1:]
+		case LongBack:
+			rolls = rolls[:len(rolls)-1]
+		}
+	}
+
+	dates := append(append([]time.Time{start}, rolls...), end)
+	periods := make([]Period, len(dates)-1)
+	adjusted := make([]time.Time, len(dates))
+	for i, d := range dates {
+		var err error
+		if adjusted[i], err = cal.Adjust(d, bdc); err != nil {
+			return nil, err
+		}
+	}
+	for i := range periods {
+		periods[i] = Period{
+			Start:         dates[i],
+			End:           dates[i+1],
+			AdjustedStart: adjusted[i],
+			AdjustedEnd:   adjusted[i+1],
+		}
+	}
+	if irregular {
+		if backward {
+			periods[0].IsStub = true
+		} else {
+			periods[len(periods)-1].IsStub = true
+		}
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,165 @@
+package cashflow
+
+import (
+	"errors"
+	"math"
+)
+
+type AmortizationType string
+
+const (
+	LevelPayment AmortizationType = "level"
+	Linear       AmortizationType = "linear" // Equal principal each period
+	InterestOnly AmortizationType = "interest_only"
+	Balloon      AmortizationType = "balloon" // Level payment on AmortizationPeriods, balance due at Periods
+)
+
+type PrepaymentModel string
+
+const (
+	NoPrepayment PrepaymentModel = ""
+	CPR          PrepaymentModel = "cpr" // Constant annual prepayment rate; Speed is the rate
+	PSA          PrepaymentModel = "psa" // 0.2% CPR a month ramping to 6% at month 30; Speed is % of PSA
+)
+
+// Loan has a nominal annual Rate paid Frequency times a year over Periods payments
+type Loan struct {
+	Principal           float64          `json:"principal"`
+	Rate                float64          `json:"rate"`
+	Periods             int              `json:"periods"`
+	Frequency           int              `json:"frequency"`
+	Type                AmortizationType `json:"type"`
+	AmortizationPeriods int              `json:"amortization_periods"`
+	Prepayment          PrepaymentModel  `json:"prepayment"`
+	Speed               float64          `json:"speed"`
+}
+
+type ScheduleRow struct {
+	Period             int     `json:"period"`
+	Time               float64 `json:"time"` // Years
+	BeginBalance       float64 `json:"begin_balance"`
+	Payment            float64 `json:"payment"`
+	Interest           float64 `json:"interest"`
+	ScheduledPrincipal float64 `json:"scheduled_principal"`
+	Prepayment         float64 `json:"prepayment"`
+	EndBalance         float64 `json:"end_balance"`
+}
+
+type AmortizationResult struct {
+	Schedule      []ScheduleRow `json:"schedule"`
+	TotalPayment  float64       `json:"total_payment"`
+	TotalInterest float64       `json:"total_interest"`
+	WAL           float64       `json:"wal"` // Principal-weighted average life in years
+}
+
+func (l Loan) Validate() error {
+	if l.Principal <= 0 || l.Periods <= 0 || l.Periods > 1200 {
+		return errors.New("principal must be positive and periods between 1 and 1200")
+	}
+	if l.Rate < 0 || l.Frequency <= 0 || l.Frequency > 365 {
+		return errors.New("rate cannot be negative and frequency must be between 1 and 365")
+	}
+	switch l.Type {
+	case LevelPayment, Linear, InterestOnly:
+	case Balloon:
+		if l.AmortizationPeriods < l.Periods || l.AmortizationPeriods > 1200 {
+			return errors.New("balloon loans need amortization_periods between periods and 1200")
+		}
+	default:
+		return errors.New("type must be level, linear, interest_only or balloon")
+	}
+	switch l.Prepayment {
+	case NoPrepayment:
+	case CPR:
+		if l.Speed < 0 || l.Speed >= 1 {
+			return errors.New("CPR speed must be in [0, 1)")
+		}
+	case PSA:
+		if l.Speed < 0 || l.Speed > 5000 {
+			return errors.New("PSA speed must be between 0 and 5000")
+		}
+	default:
+		return errors.New("prepayment must be cpr or psa")
+	}
+	return nil
+}
+
+// smm is the single-period prepayment rate for period k (from 1)
+func (l Loan) smm(k int) float64 {
+	var cpr float64
+	switch l.Prepayment {
+	case CPR:
+		cpr = l.Speed
+	case PSA:
+		month := math.Ceil(float64(k) * 12 / float64(l.Frequency))
+		cpr = math.Min(0.06*math.Min(month, 30)/30*l.Speed/100, 0.999999)
+	default:
+		return 0
+	}
+	return 1 - math.Pow(1-cpr, 1/float64(l.Frequency))
+}
+
+// levelPayment amortises balance over n periods at the periodic rate r
+func levelPayment(balance, r float64, n int) float64 {
+	if r == 0 {
+		return balance / float64(n)
+	}
+	return balance * r / -math.Expm1(-float64(n)*math.Log1p(r))
+}
+
+// Schedule amortises the loan period by period. Scheduled payments are re-sized on
+// the surviving balance after prepayments, as for a pool of mortgages.
+func (l Loan) Schedule() (AmortizationResult, error) {
+	if err := l.Validate(); err != nil {
+		return AmortizationResult{}, err
+	}
+
+	r := l.Rate / float64(l.Frequency)
+	amortTerm := l.Periods
+	if l.Type == Balloon {
+		amortTerm = l.AmortizationPeriods
+	}
+
+	result := AmortizationResult{Schedule: make([]ScheduleRow, 0, l.Periods)}
+	balance, weighted := l.Principal, 0.0
+	for k := 1; k <= l.Periods && balance > 1e-9*l.Principal; k++ {
+		row := ScheduleRow{Period: k, Time: float64(k) / float64(l.Frequency), BeginBalance: balance}
+		row.Interest = balance * r
+
+		remaining := amortTerm - k + 1
+		switch l.Type {
+		case LevelPayment, Balloon:
+			row.ScheduledPrincipal = levelPayment(balance, r, remaining) - row.Interest
+		case Linear:
+			row.ScheduledPrincipal = balance / float64(remaining)
+		}
+		if k == l.Periods {
+			row.ScheduledPrincipal = balance
+		}
+		row.Prepayment = (balance - row.ScheduledPrincipal) * l.smm(k)
+
+		principal := row.ScheduledPrincipal + row.Prepayment
+		row.Payment = row.Interest + principal
+		balance -= principal
+		if balance < 1e-9*l.Principal {
+			balance = 0
+		}
+		row.EndBalance = balance
+
+		result.TotalPayment += row.Payment
+		result.TotalInterest += row.Interest
+		weighted += principal * row.Time
+		result.Schedule = append(result.Schedule, row)
+	}
+	result.WAL = weighted / l.Principal
+	return result, nil
+}
+
+// Flows returns the lender's payments as periodic flows starting at period 1
+func (a AmortizationResult) Flows() []Flow {
+	flows := make([]Flow, len(a.Schedule))
+	for i, row := range a.Schedule {
+		flows[i] = Flow{Time: float64(row.Period), Amount: row.Payment}
+	}
+	return flows
+}
@@ -0,0 +1,54 @@
+package cashflow
+
+import (
+	"errors"
+	"math"
+)
+
+// Annuity pays Payment at the end of each of Periods periods (the start when Due),
+// growing by Growth per period. Periods of zero means a perpetuity.
+type Annuity struct {
+	Payment float64 `json:"payment"`
+	Rate    float64 `json:"rate"`
+	Periods int     `json:"periods"`
+	Growth  float64 `json:"growth"`
+	Due     bool    `json:"due"`
+}
+
+type AnnuityResult struct {
+	PresentValue float64  `json:"present_value"`
+	FutureValue  *float64 `json:"future_value"` // Not defined for a perpetuity
+}
+
+func (a Annuity) Value() (AnnuityResult, error) {
+	if a.Rate <= -1 || a.Growth <= -1 {
+		return AnnuityResult{}, errors.New("rate and growth must be greater than -100%")
+	}
+	if a.Periods < 0 {
+		return AnnuityResult{}, errors.New("periods cannot be negative")
+	}
+
+	timing := 1.0
+	if a.Due {
+		timing = 1 + a.Rate
+	}
+
+	if a.Periods == 0 {
+		if a.Rate <= a.Growth {
+			return AnnuityResult{}, errors.New("a perpetuity needs rate above growth")
+		}
+		return AnnuityResult{PresentValue: a.Payment / (a.Rate - a.Growth) * timing}, nil
+	}
+
+	n := float64(a.Periods)
+	var pv float64
+	switch {
+	case math.Abs(a.Rate-a.Growth) < 1e-12:
+		pv = a.Payment * n / (1 + a.Rate)
+	default:
+		pv = a.Payment / (a.Rate - a.Growth) * (1 - math.Pow((1+a.Growth)/(1+a.Rate), n))
+	}
+	pv *= timing
+	fv := pv * math.Pow(1+a.Rate, n)
+	return AnnuityResult{PresentValue: pv, FutureValue: &fv}, nil
+}
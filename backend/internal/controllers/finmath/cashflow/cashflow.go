@@ -0,0 +1,192 @@
+package cashflow
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Flow is an amount at Time, measured in periods for periodic flows and in
+// ACT/365 years from the first date for dated ones. Rates are per unit of Time.
+type Flow struct {
+	Time   float64 `json:"time"`
+	Amount float64 `json:"amount"`
+}
+
+// Periodic places amounts at times 0, 1, 2, ...
+func Periodic(amounts []float64) []Flow {
+	flows := make([]Flow, len(amounts))
+	for i, a := range amounts {
+		flows[i] = Flow{Time: float64(i), Amount: a}
+	}
+	return flows
+}
+
+// Dated measures times from the earliest date as XIRR does and sorts the flows.
+// Times count calendar days, so dates centuries apart are measured exactly.
+func Dated(dates []time.Time, amounts []float64) ([]Flow, error) {
+	if len(dates) != len(amounts) {
+		return nil, errors.New("dates and amounts must have the same length")
+	}
+	if len(dates) == 0 {
+		return nil, nil
+	}
+	first := dates[0]
+	for _, d := range dates {
+		if d.Before(first) {
+			first = d
+		}
+	}
+	flows := make([]Flow, len(dates))
+	for i, d := range dates {
+		flows[i] = Flow{Time: calendar.Act365Fixed.YearFraction(first, d, nil), Amount: amounts[i]}
+	}
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].Time < flows[j].Time })
+	return flows, nil
+}
+
+// PresentValue discounts each flow with discount(Time)
+func PresentValue(flows []Flow, discount func(t float64) float64) float64 {
+	pv := 0.0
+	for _, f := range flows {
+		pv += f.Amount * discount(f.Time)
+	}
+	return pv
+}
+
+// NPV discounts at a compound rate per unit of time
+func NPV(flows []Flow, rate float64) (float64, error) {
+	if rate <= -1 {
+		return 0, errors.New("rate must be greater than -100%")
+	}
+	return npv(flows, math.Log1p(rate)), nil
+}
+
+// npv takes the continuously compounded equivalent x = ln(1 + rate)
+func npv(flows []Flow, x float64) float64 {
+	return PresentValue(flows, func(t float64) float64 { return math.Exp(-x * t) })
+}
+
+type IRRResult struct {
+	Rate        float64   `json:"rate"`
+	Rates       []float64 `json:"rates"` // Every root found between -99% and 10,000%
+	SignChanges int       `json:"sign_changes"`
+	Multiple    bool      `json:"multiple"` // More than one sign change, so the IRR may not be unique
+}
+
+// IRR scans NPV on a grid in ln(1 + r) for sign changes and polishes each bracket
+// with Brent. Rate is the root closest to 10%, Excel's default guess.
+func IRR(flows []Flow) (IRRResult, error) {
+	hasPos, hasNeg := false, false
+	changes, last := 0, 0.0
+	for _, f := range flows {
+		if f.Amount > 0 {
+			hasPos = true
+		} else if f.Amount < 0 {
+			hasNeg = true
+		}
+		if f.Amount != 0 {
+			if last != 0 && (f.Amount > 0) != (last > 0) {
+				changes++
+			}
+			last = f.Amount
+		}
+	}
+	if !hasPos || !hasNeg {
+		return IRRResult{}, errors.New("cash flows need both positive and negative amounts")
+	}
+
+	const gridPoints = 2000
+	lo, hi := math.Log(0.01), math.Log(101)
+	step := (hi - lo) / gridPoints
+	f := func(x float64) float64 { return npv(flows, x) }
+
+	var roots []float64
+	prevX, prevV := lo, f(lo)
+	for i := 1; i <= gridPoints; i++ {
+		x := lo + float64(i)*step
+		v := f(x)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			prevX, prevV = x, v
+			continue
+		}
+		switch {
+		case v == 0:
+			roots = append(roots, x)
+		case prevV != 0 && !math.IsNaN(prevV) && !math.IsInf(prevV, 0) && (v > 0) != (prevV > 0):
+			root, err := opt.BrentRoot(f, prevX, x, 1e-14)
+			if err == nil {
+				roots = append(roots, root)
+			}
+		}
+		prevX, prevV = x, v
+	}
+	if len(roots) == 0 {
+		return IRRResult{}, errors.New("no IRR between -99% and 10,000%")
+	}
+
+	result := IRRResult{SignChanges: changes, Multiple: changes > 1 || len(roots) > 1}
+	best := math.Inf(1)
+	for _, x := range roots {
+		r := math.Expm1(x)
+		result.Rates = append(result.Rates, r)
+		if d := math.Abs(r - 0.1); d < best {
+			best, result.Rate = d, r
+		}
+	}
+	return result, nil
+}
+
+// MIRR compounds positive flows to the last time at reinvestRate and discounts
+// negative flows to time zero at financeRate
+func MIRR(flows []Flow, financeRate, reinvestRate float64) (float64, error) {
+	if financeRate <= -1 || reinvestRate <= -1 {
+		return 0, errors.New("rates must be greater than -100%")
+	}
+	horizon := 0.0
+	for _, f := range flows {
+		horizon = math.Max(horizon, f.Time)
+	}
+	if horizon <= 0 {
+		return 0, errors.New("cash flows must span more than one date")
+	}
+
+	pvNeg, fvPos := 0.0, 0.0
+	for _, f := range flows {
+		if f.Amount < 0 {
+			pvNeg += f.Amount * math.Pow(1+financeRate, -f.Time)
+		} else {
+			fvPos += f.Amount * math.Pow(1+reinvestRate, horizon-f.Time)
+		}
+	}
+	if pvNeg == 0 || fvPos == 0 {
+		return 0, errors.New("cash flows need both positive and negative amounts")
+	}
+	return math.Pow(fvPos/-pvNeg, 1/horizon) - 1, nil
+}
+
+// Payback returns the first time at which cumulative flows discounted at rate
+// recover from below zero, interpolating linearly within the period. Flows that
+// never go negative pay back at once; false means they are never recovered.
+func Payback(flows []Flow, rate float64) (float64, bool) {
+	if rate <= -1 || len(flows) == 0 {
+		return 0, false
+	}
+	cumulative, prevTime, invested := 0.0, flows[0].Time, false
+	for _, f := range flows {
+		pv := f.Amount * math.Pow(1+rate, -f.Time)
+		if cumulative < 0 && cumulative+pv >= 0 {
+			return prevTime + (f.Time-prevTime)*(-cumulative/pv), true
+		}
+		cumulative += pv
+		invested = invested || cumulative < 0
+		prevTime = f.Time
+	}
+	if !invested {
+		return flows[0].Time, true
+	}
+	return 0, false
+}
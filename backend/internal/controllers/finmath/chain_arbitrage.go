@@ -0,0 +1,207 @@
+package finmath
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ChainOption is one listed European contract. Bid and Ask both default to Price
+// when neither is given, so a chain of mids is checked without transaction costs.
+// A zero bid is a real quote, as is common far out of the money.
+type ChainOption struct {
+	Strike float64  `json:"strike"`
+	Expiry float64  `json:"expiry"`
+	IsCall bool     `json:"is_call"`
+	Bid    *float64 `json:"bid"`
+	Ask    *float64 `json:"ask"`
+	Price  float64  `json:"price"`
+}
+
+type ArbitrageLeg struct {
+	Action   string  `json:"action"` // buy or sell
+	Type     string  `json:"type"`   // call, put, underlying or bond
+	Strike   float64 `json:"strike,omitempty"`
+	Expiry   float64 `json:"expiry,omitempty"`
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+// ChainViolation is a static arbitrage; Profit is its value today after paying
+// the ask on purchases and receiving the bid on sales
+type ChainViolation struct {
+	Type   string         `json:"type"`
+	Expiry float64        `json:"expiry"`
+	Profit float64        `json:"profit"`
+	Legs   []ArbitrageLeg `json:"legs"`
+}
+
+type ChainScan struct {
+	Checks      map[string]int   `json:"checks"`
+	Violations  []ChainViolation `json:"violations"`
+	ImpliedVols []*float64       `json:"implied_vols"` // From mids, aligned with the input; nil where inversion fails
+}
+
+func (o ChainOption) quotes() (float64, float64) {
+	if o.Bid == nil || o.Ask == nil {
+		return o.Price, o.Price
+	}
+	return *o.Bid, *o.Ask
+}
+
+func (o ChainOption) leg(action string, qty float64) ArbitrageLeg {
+	bid, ask := o.quotes()
+	l := ArbitrageLeg{Action: action, Type: "put", Strike: o.Strike, Expiry: o.Expiry, Quantity: qty, Price: ask}
+	if o.IsCall {
+		l.Type = "call"
+	}
+	if action == "sell" {
+		l.Price = bid
+	}
+	return l
+}
+
+// netCash is cash received from sales less cash paid for purchases
+func netCash(legs []ArbitrageLeg) float64 {
+	total := 0.0
+	for _, l := range legs {
+		if l.Action == "sell" {
+			total += l.Quantity * l.Price
+		} else {
+			total -= l.Quantity * l.Price
+		}
+	}
+	return total
+}
+
+// ScanChain checks a European option chain for put-call parity, vertical spread,
+// butterfly and calendar arbitrage between neighbouring strikes and expiries. The
+// underlying and zero bonds are assumed to trade at S e^{-qT} and K e^{-r(T)T}, with
+// r(T) the zero rate to each expiry. Only violations worth more than minProfit are
+// reported.
+func ScanChain(S float64, rates Rates, q float64, options []ChainOption, minProfit float64) (ChainScan, error) {
+	if S <= 0 {
+		return ChainScan{}, errors.New("spot price must be positive")
+	}
+	for _, o := range options {
+		if (o.Bid == nil) != (o.Ask == nil) {
+			return ChainScan{}, errors.New("options need both a bid and an ask, or neither with a price")
+		}
+		bid, ask := o.quotes()
+		if o.Strike <= 0 || o.Expiry <= 0 || bid < 0 || ask < bid {
+			return ChainScan{}, errors.New("options need positive strikes and expiries and 0 <= bid <= ask")
+		}
+	}
+
+	scan := ChainScan{
+		Checks:      map[string]int{"parity": 0, "vertical": 0, "butterfly": 0, "calendar": 0},
+		Violations:  []ChainViolation{},
+		ImpliedVols: make([]*float64, len(options)),
+	}
+	check := func(kind string, expiry float64, legs ...ArbitrageLeg) {
+		scan.Checks[kind]++
+		if p := netCash(legs); p > minProfit {
+			scan.Violations = append(scan.Violations, ChainViolation{Type: kind, Expiry: expiry, Profit: p, Legs: legs})
+		}
+	}
+
+	for i, o := range options {
+		bid, ask := o.quotes()
+		if vol, err := ImpliedVolatilityWithCarry(S, o.Strike, o.Expiry, rates.ZeroRate(o.Expiry), q, (bid+ask)/2, o.IsCall); err == nil {
+			scan.ImpliedVols[i] = &vol
+		}
+	}
+
+	// chains[T][isCall] holds one quote per strike, ascending
+	chains := map[float64]map[bool][]ChainOption{}
+	var expiries []float64
+	for _, o := range options {
+		if chains[o.Expiry] == nil {
+			chains[o.Expiry] = map[bool][]ChainOption{}
+			expiries = append(expiries, o.Expiry)
+		}
+		chains[o.Expiry][o.IsCall] = append(chains[o.Expiry][o.IsCall], o)
+	}
+	sort.Float64s(expiries)
+	for _, T := range expiries {
+		for isCall, chain := range chains[T] {
+			sort.SliceStable(chain, func(i, j int) bool { return chain[i].Strike < chain[j].Strike })
+			unique := chain[:0]
+			for _, o := range chain {
+				if len(unique) == 0 || unique[len(unique)-1].Strike != o.Strike {
+					unique = append(unique, o)
+				}
+			}
+			chains[T][isCall] = unique
+		}
+	}
+
+	bond := func(action string, K, T float64) ArbitrageLeg {
+		return ArbitrageLeg{Action: action, Type: "bond", Expiry: T, Quantity: K, Price: math.Exp(-rates.ZeroRate(T) * T)}
+	}
+	stock := func(action string, T float64) ArbitrageLeg {
+		return ArbitrageLeg{Action: action, Type: "underlying", Quantity: math.Exp(-q * T), Price: S}
+	}
+
+	for _, T := range expiries {
+		for _, isCall := range []bool{true, false} {
+			chain := chains[T][isCall]
+			for i := 1; i < len(chain); i++ {
+				lo, hi := chain[i-1], chain[i]
+				// The option nearer the money is worth more, by at most the discounted strike gap
+				rich, cheap := lo, hi
+				if !isCall {
+					rich, cheap = hi, lo
+				}
+				check("vertical", T, rich.leg("buy", 1), cheap.leg("sell", 1))
+				check("vertical", T, rich.leg("sell", 1), cheap.leg("buy", 1), bond("buy", hi.Strike-lo.Strike, T))
+			}
+			for i := 2; i < len(chain); i++ {
+				k1, k2, k3 := chain[i-2], chain[i-1], chain[i]
+				w := (k3.Strike - k2.Strike) / (k3.Strike - k1.Strike)
+				check("butterfly", T, k1.leg("buy", w), k2.leg("sell", 1), k3.leg("buy", 1-w))
+			}
+		}
+
+		// Conversions and reversals: C - P = S e^{-qT} - K e^{-rT}
+		puts := map[float64]ChainOption{}
+		for _, p := range chains[T][false] {
+			puts[p.Strike] = p
+		}
+		for _, call := range chains[T][true] {
+			put, ok := puts[call.Strike]
+			if !ok {
+				continue
+			}
+			K := call.Strike
+			check("parity", T, stock("buy", T), put.leg("buy", 1), call.leg("sell", 1), bond("sell", K, T))
+			check("parity", T, stock("sell", T), put.leg("sell", 1), call.leg("buy", 1), bond("buy", K, T))
+		}
+	}
+
+	// Calendars: prices normalised by S e^{-qT} at fixed K/F(T) cannot fall with
+	// expiry. The later leg is bought as the two strikes bracketing the matching
+	// moneyness, which by convexity dominates the exact strike.
+	for e := 1; e < len(expiries); e++ {
+		T1, T2 := expiries[e-1], expiries[e]
+		growth := math.Exp(rates.ZeroRate(T2)*T2 - rates.ZeroRate(T1)*T1 - q*(T2-T1))
+		scale := math.Exp(q * (T2 - T1))
+		for _, isCall := range []bool{true, false} {
+			later := chains[T2][isCall]
+			for _, near := range chains[T1][isCall] {
+				target := near.Strike * growth
+				j := sort.Search(len(later), func(i int) bool { return later[i].Strike >= target })
+				switch {
+				case j < len(later) && math.Abs(later[j].Strike-target) < 1e-9*target:
+					check("calendar", T1, near.leg("sell", 1), later[j].leg("buy", scale))
+				case j > 0 && j < len(later):
+					a, b := later[j-1], later[j]
+					w := (b.Strike - target) / (b.Strike - a.Strike)
+					check("calendar", T1, near.leg("sell", 1), a.leg("buy", scale*w), b.leg("buy", scale*(1-w)))
+				}
+			}
+		}
+	}
+
+	return scan, nil
+}
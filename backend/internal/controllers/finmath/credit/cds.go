@@ -0,0 +1,200 @@
+package credit
+
+import (
+	"backend/internal/controllers/finmath/curve"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+	"sort"
+)
+
+// HazardCurve is a piecewise-constant default intensity: Hazards[i] applies up to
+// Times[i], and the last one is held beyond the final node
+type HazardCurve struct {
+	Times   []float64 `json:"times"`
+	Hazards []float64 `json:"hazards"`
+}
+
+func (h HazardCurve) Validate() error {
+	if len(h.Times) == 0 || len(h.Times) != len(h.Hazards) {
+		return errors.New("hazard curve needs matching, non-empty times and hazards")
+	}
+	for i, t := range h.Times {
+		if t <= 0 || (i > 0 && t <= h.Times[i-1]) {
+			return errors.New("hazard curve times must be positive and increasing")
+		}
+		if h.Hazards[i] < 0 {
+			return errors.New("hazard rates cannot be negative")
+		}
+	}
+	return nil
+}
+
+// Survival is Q(t) = exp(-∫₀ᵗ h(s) ds)
+func (h HazardCurve) Survival(t float64) float64 {
+	integral, prev := 0.0, 0.0
+	for i, node := range h.Times {
+		if t <= node {
+			return math.Exp(-(integral + h.Hazards[i]*(t-prev)))
+		}
+		integral += h.Hazards[i] * (node - prev)
+		prev = node
+	}
+	return math.Exp(-(integral + h.Hazards[len(h.Hazards)-1]*(t-prev)))
+}
+
+func (h HazardCurve) Hazard(t float64) float64 {
+	i := sort.SearchFloat64s(h.Times, t)
+	if i >= len(h.Hazards) {
+		i = len(h.Hazards) - 1
+	}
+	return h.Hazards[i]
+}
+
+type SurvivalPoint struct {
+	Time               float64 `json:"time"`
+	Survival           float64 `json:"survival"`
+	DefaultProbability float64 `json:"default_probability"`
+	Hazard             float64 `json:"hazard"`
+}
+
+func (h HazardCurve) Sample(tenors []float64) []SurvivalPoint {
+	points := make([]SurvivalPoint, len(tenors))
+	for i, t := range tenors {
+		q := h.Survival(t)
+		points[i] = SurvivalPoint{Time: t, Survival: q, DefaultProbability: 1 - q, Hazard: h.Hazard(t)}
+	}
+	return points
+}
+
+// CDS is a credit default swap from today to Maturity, paying the running Spread
+// Frequency times a year with accrued premium on default, against (1 - Recovery)
+// of Notional on default
+type CDS struct {
+	Maturity  float64 `json:"maturity"`
+	Spread    float64 `json:"spread"`
+	Recovery  float64 `json:"recovery"`
+	Frequency int     `json:"frequency"`
+	Notional  float64 `json:"notional"`
+}
+
+type CDSResult struct {
+	PremiumLeg    float64 `json:"premium_leg"`
+	ProtectionLeg float64 `json:"protection_leg"`
+	NPV           float64 `json:"npv"` // To the protection buyer
+	ParSpread     float64 `json:"par_spread"`
+	RiskyPV01     float64 `json:"risky_pv01"` // Premium leg value of 1bp
+	Upfront       float64 `json:"upfront"`    // NPV as a fraction of notional
+}
+
+func (c CDS) validate() error {
+	if c.Maturity <= 0 || c.Maturity > 100 || c.Frequency <= 0 || c.Frequency > 12 {
+		return errors.New("CDS needs a maturity up to 100 years and a frequency between 1 and 12")
+	}
+	if c.Recovery < 0 || c.Recovery >= 1 {
+		return errors.New("recovery must be in [0, 1)")
+	}
+	return nil
+}
+
+// Integration steps per premium period for the protection and accrual terms
+const stepsPerPeriod = 16
+
+// legs returns the risky annuity per unit spread and notional and the protection
+// leg per unit loss and notional
+func (c CDS) legs(discount curve.Curve, hazard HazardCurve) (float64, float64) {
+	tau := 1 / float64(c.Frequency)
+	n := int(math.Ceil(c.Maturity/tau - 1e-9))
+
+	annuity, protection := 0.0, 0.0
+	start := 0.0
+	for i := 1; i <= n; i++ {
+		end := math.Min(float64(i)*tau, c.Maturity)
+		annuity += (end - start) * discount.Discount(end) * hazard.Survival(end)
+
+		// Midpoint rule on the default density within the period, which also pays
+		// the premium accrued since the last coupon
+		dt := (end - start) / stepsPerPeriod
+		prevQ := hazard.Survival(start)
+		for k := 1; k <= stepsPerPeriod; k++ {
+			t := start + float64(k)*dt
+			q := hazard.Survival(t)
+			df := discount.Discount(t - dt/2)
+			protection += df * (prevQ - q)
+			annuity += (t - dt/2 - start) * df * (prevQ - q)
+			prevQ = q
+		}
+		start = end
+	}
+	return annuity, protection
+}
+
+func (c CDS) Price(discount curve.Curve, hazard HazardCurve) (CDSResult, error) {
+	if err := c.validate(); err != nil {
+		return CDSResult{}, err
+	}
+	if err := hazard.Validate(); err != nil {
+		return CDSResult{}, err
+	}
+	notional := c.Notional
+	if notional == 0 {
+		notional = 1
+	}
+
+	annuity, protection := c.legs(discount, hazard)
+	result := CDSResult{
+		PremiumLeg:    c.Spread * annuity * notional,
+		ProtectionLeg: (1 - c.Recovery) * protection * notional,
+		ParSpread:     (1 - c.Recovery) * protection / annuity,
+		RiskyPV01:     annuity * notional * 1e-4,
+	}
+	result.NPV = result.ProtectionLeg - result.PremiumLeg
+	result.Upfront = result.NPV / notional
+	return result, nil
+}
+
+type CDSQuote struct {
+	Maturity float64 `json:"maturity"`
+	Spread   float64 `json:"spread"`
+}
+
+// BootstrapHazard solves for one hazard rate per quote, in maturity order, so
+// that each CDS prices at par
+func BootstrapHazard(quotes []CDSQuote, recovery float64, frequency int, discount curve.Curve) (HazardCurve, error) {
+	if len(quotes) == 0 {
+		return HazardCurve{}, errors.New("at least one CDS quote is required")
+	}
+	sorted := append([]CDSQuote(nil), quotes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Maturity < sorted[j].Maturity })
+
+	h := HazardCurve{}
+	for i, q := range sorted {
+		if q.Spread <= 0 || (i > 0 && q.Maturity == sorted[i-1].Maturity) {
+			return HazardCurve{}, errors.New("quote spreads must be positive and maturities distinct")
+		}
+		cds := CDS{Maturity: q.Maturity, Spread: q.Spread, Recovery: recovery, Frequency: frequency}
+		if err := cds.validate(); err != nil {
+			return HazardCurve{}, err
+		}
+
+		h.Times = append(h.Times, q.Maturity)
+		h.Hazards = append(h.Hazards, 0)
+		objective := func(lambda float64) float64 {
+			h.Hazards[i] = lambda
+			annuity, protection := cds.legs(discount, h)
+			return (1-recovery)*protection - q.Spread*annuity
+		}
+		if objective(0) > 0 {
+			return HazardCurve{}, errors.New("CDS spreads imply a negative hazard rate")
+		}
+		if objective(50) < 0 {
+			return HazardCurve{}, errors.New("CDS spreads imply a hazard rate above 5000%")
+		}
+		lambda, err := opt.BrentRoot(objective, 0, 50, 1e-14)
+		if err != nil {
+			return HazardCurve{}, err
+		}
+		h.Hazards[i] = lambda
+	}
+	return h, nil
+}
@@ -0,0 +1,89 @@
+package credit
+
+import (
+	"backend/internal/controllers/dist"
+	"backend/internal/controllers/finmath"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+// MertonInputs describe the firm's equity and a single zero-coupon debt claim of
+// face Debt due at T. Drift defaults to r when nil, giving risk-neutral odds.
+type MertonInputs struct {
+	EquityValue float64  `json:"equity_value"`
+	EquityVol   float64  `json:"equity_vol"`
+	Debt        float64  `json:"debt"`
+	T           float64  `json:"time_to_maturity"`
+	R           float64  `json:"risk_free_rate"`
+	Drift       *float64 `json:"drift"`
+}
+
+type MertonResult struct {
+	AssetValue         float64 `json:"asset_value"`
+	AssetVol           float64 `json:"asset_vol"`
+	DistanceToDefault  float64 `json:"distance_to_default"`
+	DefaultProbability float64 `json:"default_probability"`
+	DebtValue          float64 `json:"debt_value"`
+	CreditSpread       float64 `json:"credit_spread"`
+	Leverage           float64 `json:"leverage"` // Debt over asset value
+}
+
+// Merton treats equity as a call on the firm's assets struck at the debt's face and
+// solves E = C(V, σV) and σE E = N(d1) σV V for the asset value and volatility
+func Merton(in MertonInputs) (MertonResult, error) {
+	if in.EquityValue <= 0 || in.EquityVol <= 0 || in.Debt <= 0 || in.T <= 0 {
+		return MertonResult{}, errors.New("equity value, equity vol, debt and maturity must be positive")
+	}
+
+	// Asset value consistent with the equity price for a given asset vol
+	assetValue := func(sigmaV float64) (float64, error) {
+		lo := in.EquityValue
+		hi := in.EquityValue + in.Debt*math.Max(1, math.Exp(-in.R*in.T))
+		return opt.BrentRoot(func(V float64) float64 {
+			call, _ := finmath.BlackScholes(V, in.Debt, in.T, in.R, sigmaV)
+			return call - in.EquityValue
+		}, lo, hi, 1e-12*hi)
+	}
+	volGap := func(sigmaV float64) float64 {
+		V, err := assetValue(sigmaV)
+		if err != nil {
+			return math.NaN()
+		}
+		delta := finmath.Greeks(V, in.Debt, in.T, in.R, sigmaV)["call_delta"]
+		return delta*sigmaV*V - in.EquityVol*in.EquityValue
+	}
+
+	// σV lies below σE since equity is levered, so search (0, σE]
+	lo, hi := 1e-6, in.EquityVol
+	gLo, gHi := volGap(lo), volGap(hi)
+	if math.IsNaN(gLo) || math.IsNaN(gHi) || gLo*gHi > 0 {
+		return MertonResult{}, errors.New("no asset value and volatility match the equity inputs")
+	}
+	sigmaV, err := opt.BrentRoot(volGap, lo, hi, 1e-12)
+	if err != nil {
+		return MertonResult{}, err
+	}
+	V, err := assetValue(sigmaV)
+	if err != nil {
+		return MertonResult{}, err
+	}
+
+	mu := in.R
+	if in.Drift != nil {
+		mu = *in.Drift
+	}
+	sqrtT := math.Sqrt(in.T)
+	dd := (math.Log(V/in.Debt) + (mu-sigmaV*sigmaV/2)*in.T) / (sigmaV * sqrtT)
+
+	debtValue := V - in.EquityValue
+	return MertonResult{
+		AssetValue:         V,
+		AssetVol:           sigmaV,
+		DistanceToDefault:  dd,
+		DefaultProbability: dist.StandardNormalCDF(-dd),
+		DebtValue:          debtValue,
+		CreditSpread:       -math.Log(debtValue/in.Debt)/in.T - in.R,
+		Leverage:           in.Debt / V,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package curve
+
+import (
+	"backend/internal/controllers/opt"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxMaturity bounds instrument and bond maturities in years, which also bounds
+// the coupon loops evaluated inside every root search
+const maxMaturity = 100
+
+// Instrument is a market quote used to bootstrap a discount curve. Times are in
+// years from today; FRAs and futures accrue over [Start, Maturity], deposits and
+// swaps start at Start (usually 0).
+type Instrument struct {
+	Type                string  `json:"type"` // deposit, fra, future or swap
+	Start               float64 `json:"start"`
+	Maturity            float64 `json:"maturity"`
+	Rate                float64 `json:"rate"`
+	Price               float64 `json:"price"`                // Futures price, implying a rate of (100 - price)/100
+	ConvexityAdjustment float64 `json:"convexity_adjustment"` // Subtracted from the futures rate
+	Frequency           int     `json:"frequency"`            // Swap fixed-leg payments per year, default 1
+}
+
+type CurvePoint struct {
+	Tenor       float64 `json:"tenor"`
+	Discount    float64 `json:"discount_factor"`
+	ZeroRate    float64 `json:"zero_rate"`
+	ForwardRate float64 `json:"forward_rate"`
+}
+
+func (in *Instrument) validate() error {
+	switch in.Type {
+	case "deposit", "fra", "swap":
+	case "future":
+		if in.Price != 0 {
+			in.Rate = (100 - in.Price) / 100
+		}
+		in.Rate -= in.ConvexityAdjustment
+		if in.Maturity == 0 {
+			in.Maturity = in.Start + 0.25
+		}
+	default:
+		return errors.New("instrument type must be deposit, fra, future or swap")
+	}
+	if in.Start < 0 || in.Maturity <= in.Start {
+		return errors.New("instrument maturity must be after its start")
+	}
+	if !(in.Maturity <= maxMaturity) {
+		return errors.New("instrument maturity cannot exceed 100 years")
+	}
+	if in.Type == "swap" {
+		if in.Frequency == 0 {
+			in.Frequency = 1
+		}
+		if in.Frequency < 0 || in.Frequency > 12 {
+			return errors.New("swap frequency must be between 1 and 12")
+		}
+	}
+	return nil
+}
+
+// residual is zero when the curve reprices the instrument
+func (in Instrument) residual(c Curve) float64 {
+	if in.Type != "swap" {
+		return c.Discount(in.Maturity)*(1+in.Rate*(in.Maturity-in.Start)) - c.Discount(in.Start)
+	}
+
+	// Fixed leg rolls back from maturity; a short first period absorbs the remainder
+	annuity := 0.0
+	step := 1 / float64(in.Frequency)
+	for end := in.Maturity; end > in.Start+1e-9; end -= step {
+		annuity += (end - math.Max(end-step, in.Start)) * c.Discount(end)
+	}
+	return in.Rate*annuity - (c.Discount(in.Start) - c.Discount(in.Maturity))
+}
+
+// Bootstrap solves one discount factor per instrument maturity so that every
+// instrument reprices exactly. Non-local interpolations couple the nodes, so the
+// solve sweeps the instruments until the factors stop moving.
+func Bootstrap(instruments []Instrument, interpolation Interpolation) (*DiscountCurve, error) {
+	if len(instruments) == 0 {
+		return nil, errors.New("at least one instrument is required")
+	}
+
+	quotes := make([]Instrument, len(instruments))
+	copy(quotes, instruments)
+	for i := range quotes {
+		if err := quotes[i].validate(); err != nil {
+			return nil, fmt.Errorf("instrument %d: %w", i, err)
+		}
+	}
+	sort.SliceStable(quotes, func(i, j int) bool { return quotes[i].Maturity < quotes[j].Maturity })
+
+	times := make([]float64, len(quotes))
+	discounts := make([]float64, len(quotes))
+	for i, q := range quotes {
+		if i > 0 && q.Maturity-times[i-1] < 1e-9 {
+			return nil, errors.New("instruments must have distinct maturities")
+		}
+		times[i] = q.Maturity
+		discounts[i] = math.Exp(-q.Rate * q.Maturity)
+	}
+
+	for pass := 0; pass < 100; pass++ {
+		maxChange := 0.0
+		for k, q := range quotes {
+			trial := make([]float64, len(discounts))
+			f := func(df float64) float64 {
+				copy(trial, discounts)
+				trial[k] = df
+				c, err := NewDiscountCurve(times, trial, interpolation)
+				if err != nil {
+					return math.NaN()
+				}
+				return q.residual(c)
+			}
+
+			lo, hi := discounts[k]*0.5, math.Min(discounts[k]*1.5, 10)
+			for f(lo)*f(hi) > 0 && lo > 1e-12 {
+				lo, hi = lo*0.5, math.Min(hi*1.5, 100)
+			}
+			df, err := opt.BrentRoot(f, lo, hi, 1e-15)
+			if err != nil || math.IsNaN(df) {
+				return nil, fmt.Errorf("failed to bootstrap the %s maturing at %g", q.Type, q.Maturity)
+			}
+
+			maxChange = math.Max(maxChange, math.Abs(df-discounts[k]))
+			discounts[k] = df
+		}
+		if maxChange < 1e-14 {
+			return NewDiscountCurve(times, discounts, interpolation)
+		}
+	}
+	return nil, errors.New("bootstrap did not converge")
+}
+
+// Sample evaluates a curve at each tenor. Forward rates are simple-compounded
+// over [tenor, tenor + forwardTenor].
+func Sample(c Curve, tenors []float64, forwardTenor float64) []CurvePoint {
+	if forwardTenor <= 0 {
+		forwardTenor = 0.25
+	}
+	points := make([]CurvePoint, len(tenors))
+	for i, t := range tenors {
+		points[i] = CurvePoint{
+			Tenor:       t,
+			Discount:    c.Discount(t),
+			ZeroRate:    c.ZeroRate(t),
+			ForwardRate: (c.Discount(t)/c.Discount(t+forwardTenor) - 1) / forwardTenor,
+		}
+	}
+	return points
+}
@@ -0,0 +1,99 @@
+package curve
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBootstrapReprices(t *testing.T) {
+	instruments := []Instrument{
+		{Type: "deposit", Maturity: 0.25, Rate: 0.030},
+		{Type: "deposit", Maturity: 0.5, Rate: 0.032},
+		{Type: "fra", Start: 0.5, Maturity: 0.75, Rate: 0.034},
+		{Type: "future", Start: 0.75, Price: 96.5, ConvexityAdjustment: 0.0002},
+		{Type: "swap", Maturity: 2, Rate: 0.036, Frequency: 2},
+		{Type: "swap", Maturity: 5, Rate: 0.038},
+		{Type: "swap", Maturity: 10, Rate: 0.040},
+		{Type: "swap", Maturity: 30, Rate: 0.037},
+	}
+	for _, interpolation := range []Interpolation{LogLinear, LinearZero, MonotoneConvex, CubicSpline} {
+		t.Run(string(interpolation), func(t *testing.T) {
+			c, err := Bootstrap(instruments, interpolation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, in := range instruments {
+				if err := in.validate(); err != nil {
+					t.Fatal(err)
+				}
+				if r := in.residual(c); math.Abs(r) > 1e-12 {
+					t.Errorf("instrument %d (%s %g): residual %g", i, in.Type, in.Maturity, r)
+				}
+			}
+		})
+	}
+}
+
+func TestBootstrapFlatCurve(t *testing.T) {
+	// Continuously compounded deposits at a flat rate give back that zero rate
+	rate := 0.05
+	var instruments []Instrument
+	for _, T := range []float64{0.5, 1, 2, 5} {
+		instruments = append(instruments, Instrument{Type: "deposit", Maturity: T, Rate: (math.Exp(rate*T) - 1) / T})
+	}
+	c, err := Bootstrap(instruments, LogLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, T := range []float64{0.25, 0.5, 1.5, 5, 8} {
+		if z := c.ZeroRate(T); math.Abs(z-rate) > 1e-12 {
+			t.Errorf("zero rate at %g = %v, want %v", T, z, rate)
+		}
+	}
+}
+
+func TestFitParametricRoundTrip(t *testing.T) {
+	maturities := []float64{0.25, 0.5, 1, 2, 3, 5, 7, 10, 20, 30}
+	tests := []ParametricCurve{
+		{Model: NelsonSiegel, Beta0: 0.045, Beta1: -0.02, Beta2: 0.01, Tau1: 2},
+		{Model: Svensson, Beta0: 0.04, Beta1: -0.015, Beta2: 0.02, Beta3: -0.01, Tau1: 1.5, Tau2: 8},
+	}
+	for _, truth := range tests {
+		t.Run(string(truth.Model), func(t *testing.T) {
+			var zeros []ZeroPoint
+			for _, T := range maturities {
+				zeros = append(zeros, ZeroPoint{Maturity: T, Rate: truth.ZeroRate(T)})
+			}
+			fit, err := FitParametric(truth.Model, zeros, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, T := range maturities {
+				if got, want := fit.Curve.ZeroRate(T), truth.ZeroRate(T); math.Abs(got-want) > 1e-6 {
+					t.Errorf("zero rate at %g = %.8f, want %.8f", T, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("bonds", func(t *testing.T) {
+		truth := tests[0]
+		var bonds []BondYield
+		for _, T := range []float64{1, 2, 3, 5, 7, 10, 30} {
+			b := BondYield{Maturity: T, Coupon: 0.04, Frequency: 2}
+			y, err := b.modelYield(truth, b.cashflows())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.Yield = y
+			bonds = append(bonds, b)
+		}
+		fit, err := FitParametric(NelsonSiegel, nil, bonds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fit.RMSE > 1e-6 {
+			t.Errorf("rmse = %g, curve %+v", fit.RMSE, fit.Curve)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package curve
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Curve is a continuously compounded term structure with times in years
+type Curve interface {
+	Discount(t float64) float64
+	ZeroRate(t float64) float64
+	ForwardRate(t1, t2 float64) float64
+}
+
+type Interpolation string
+
+const (
+	LogLinear      Interpolation = "log_linear"
+	LinearZero     Interpolation = "linear_zero"
+	MonotoneConvex Interpolation = "monotone_convex"
+	CubicSpline    Interpolation = "cubic_spline"
+)
+
+func ParseInterpolation(s string) (Interpolation, error) {
+	switch i := Interpolation(s); i {
+	case LogLinear, LinearZero, MonotoneConvex, CubicSpline:
+		return i, nil
+	case "":
+		return LogLinear, nil
+	}
+	return "", errors.New("interpolation must be log_linear, linear_zero, monotone_convex or cubic_spline")
+}
+
+// DiscountCurve interpolates discount factors at node times. Log-linear curves
+// extrapolate the last forward rate; the others hold the end zero rates flat.
+type DiscountCurve struct {
+	Times         []float64     `json:"times"`
+	Discounts     []float64     `json:"discount_factors"`
+	Interpolation Interpolation `json:"interpolation"`
+
+	zeros  []float64
+	spline []float64 // Second derivatives of the zero-rate spline
+	mc     *monotoneConvex
+}
+
+func NewDiscountCurve(times, discounts []float64, interpolation Interpolation) (*DiscountCurve, error) {
+	if len(times) == 0 || len(times) != len(discounts) {
+		return nil, errors.New("curve needs matching, non-empty times and discount factors")
+	}
+	for i, t := range times {
+		if t <= 0 || (i > 0 && t <= times[i-1]) {
+			return nil, errors.New("curve times must be positive and strictly increasing")
+		}
+		if discounts[i] <= 0 || math.IsNaN(discounts[i]) {
+			return nil, errors.New("discount factors must be positive")
+		}
+	}
+	if _, err := ParseInterpolation(string(interpolation)); err != nil {
+		return nil, err
+	}
+	if interpolation == "" {
+		interpolation = LogLinear
+	}
+
+	c := &DiscountCurve{
+		Times:         append([]float64(nil), times...),
+		Discounts:     append([]float64(nil), discounts...),
+		Interpolation: interpolation,
+		zeros:         make([]float64, len(times)),
+	}
+	for i, t := range times {
+		c.zeros[i] = -math.Log(discounts[i]) / t
+	}
+
+	switch interpolation {
+	case CubicSpline:
+		c.spline = naturalSpline(c.Times, c.zeros)
+	case MonotoneConvex:
+		c.mc = newMonotoneConvex(c.Times, c.zeros)
+	}
+	return c, nil
+}
+
+// logDiscount returns ln D(t)
+func (c *DiscountCurve) logDiscount(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	n := len(c.Times)
+
+	switch c.Interpolation {
+	case LinearZero, CubicSpline:
+		if t <= c.Times[0] {
+			return -c.zeros[0] * t
+		}
+		if t >= c.Times[n-1] {
+			return -c.zeros[n-1] * t
+		}
+		i := sort.SearchFloat64s(c.Times, t)
+		if c.Interpolation == CubicSpline {
+			return -evalSpline(c.Times, c.zeros, c.spline, i-1, t) * t
+		}
+		w := (t - c.Times[i-1]) / (c.Times[i] - c.Times[i-1])
+		return -(c.zeros[i-1] + w*(c.zeros[i]-c.zeros[i-1])) * t
+
+	case MonotoneConvex:
+		return -c.mc.integral(t)
+
+	default:
+		if t >= c.Times[n-1] {
+			lnLast := math.Log(c.Discounts[n-1])
+			if n == 1 {
+				return lnLast * t / c.Times[0]
+			}
+			slope := (lnLast - math.Log(c.Discounts[n-2])) / (c.Times[n-1] - c.Times[n-2])
+			return lnLast + slope*(t-c.Times[n-1])
+		}
+		i := sort.SearchFloat64s(c.Times, t)
+		t0, ln0 := 0.0, 0.0
+		if i > 0 {
+			t0, ln0 = c.Times[i-1], math.Log(c.Discounts[i-1])
+		}
+		w := (t - t0) / (c.Times[i] - t0)
+		return ln0 + w*(math.Log(c.Discounts[i])-ln0)
+	}
+}
+
+func (c *DiscountCurve) Discount(t float64) float64 {
+	return math.Exp(c.logDiscount(t))
+}
+
+// ZeroRate is continuously compounded; at t <= 0 it returns the short-end limit
+func (c *DiscountCurve) ZeroRate(t float64) float64 {
+	if t < 1e-6 {
+		t = 1e-6
+	}
+	return -c.logDiscount(t) / t
+}
+
+// ForwardRate is the continuously compounded forward over [t1, t2], or the
+// instantaneous forward when t1 == t2
+func (c *DiscountCurve) ForwardRate(t1, t2 float64) float64 {
+	if t2 < t1 {
+		t1, t2 = t2, t1
+	}
+	if t2-t1 < 1e-6 {
+		t1, t2 = math.Max(t1-5e-7, 0), t1+5e-7
+	}
+	return (c.logDiscount(t1) - c.logDiscount(t2)) / (t2 - t1)
+}
+
+// naturalSpline returns the second derivatives of a natural cubic spline through (x, y)
+func naturalSpline(x, y []float64) []float64 {
+	n := len(x)
+	m := make([]float64, n)
+	if n < 3 {
+		return m
+	}
+
+	// Thomas algorithm on the tridiagonal system for interior points
+	c := make([]float64, n)
+	d := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		h0, h1 := x[i]-x[i-1], x[i+1]-x[i]
+		rhs := 6 * ((y[i+1]-y[i])/h1 - (y[i]-y[i-1])/h0)
+		diag := 2 * (h0 + h1)
+		if i > 1 {
+			diag -= h0 * c[i-1]
+			rhs -= h0 * d[i-1]
+		}
+		c[i] = h1 / diag
+		d[i] = rhs / diag
+	}
+	for i := n - 2; i >= 1; i-- {
+		m[i] = d[i] - c[i]*m[i+1]
+	}
+	return m
+}
+
+func evalSpline(x, y, m []float64, i int, t float64) float64 {
+	h := x[i+1] - x[i]
+	a := (x[i+1] - t) / h
+	b := (t - x[i]) / h
+	return a*y[i] + b*y[i+1] + ((a*a*a-a)*m[i]+(b*b*b-b)*m[i+1])*h*h/6
+}
@@ -0,0 +1,94 @@
+package curve
+
+import (
+	"math"
+	"sort"
+)
+
+// monotoneConvex implements Hagan and West's (2006) monotone convex method: forwards
+// are continuous and positive discrete forwards stay positive. The positivity
+// collar is left out so negative-rate curves are handled unchanged.
+type monotoneConvex struct {
+	terms    []float64 // 0, t1, ..., tn
+	rt       []float64 // r(t)·t at each term
+	discrete []float64 // Discrete forward over (terms[i-1], terms[i]]
+	instant  []float64 // Instantaneous forward at each term
+}
+
+func newMonotoneConvex(times, zeros []float64) *monotoneConvex {
+	n := len(times)
+	mc := &monotoneConvex{
+		terms:    append([]float64{0}, times...),
+		rt:       make([]float64, n+1),
+		discrete: make([]float64, n+1),
+		instant:  make([]float64, n+1),
+	}
+	for i := 1; i <= n; i++ {
+		mc.rt[i] = zeros[i-1] * times[i-1]
+		mc.discrete[i] = (mc.rt[i] - mc.rt[i-1]) / (mc.terms[i] - mc.terms[i-1])
+	}
+
+	if n == 1 {
+		mc.instant[0], mc.instant[1] = mc.discrete[1], mc.discrete[1]
+		return mc
+	}
+	for i := 1; i < n; i++ {
+		left := mc.terms[i] - mc.terms[i-1]
+		right := mc.terms[i+1] - mc.terms[i]
+		mc.instant[i] = (left*mc.discrete[i+1] + right*mc.discrete[i]) / (left + right)
+	}
+	mc.instant[0] = mc.discrete[1] - 0.5*(mc.instant[1]-mc.discrete[1])
+	mc.instant[n] = mc.discrete[n] - 0.5*(mc.instant[n-1]-mc.discrete[n])
+	return mc
+}
+
+// integral returns r(t)·t, the integral of the forward curve from 0 to t
+func (mc *monotoneConvex) integral(t float64) float64 {
+	n := len(mc.terms) - 1
+	if t >= mc.terms[n] {
+		return mc.rt[n] + mc.instant[n]*(t-mc.terms[n])
+	}
+
+	i := sort.SearchFloat64s(mc.terms, t)
+	if i == 0 {
+		return 0
+	}
+	width := mc.terms[i] - mc.terms[i-1]
+	x := (t - mc.terms[i-1]) / width
+	g0 := mc.instant[i-1] - mc.discrete[i]
+	g1 := mc.instant[i] - mc.discrete[i]
+
+	return mc.rt[i-1] + mc.discrete[i]*(t-mc.terms[i-1]) + width*gIntegral(g0, g1, x)
+}
+
+// gIntegral integrates the forward's deviation from the discrete forward over
+// [0, x] of a unit interval, choosing the sector by the end-point deviations g0, g1
+func gIntegral(g0, g1, x float64) float64 {
+	switch {
+	case g0 == 0 || g1 == 0,
+		(g0 < 0 && -0.5*g0 <= g1 && g1 <= -2*g0) || (g0 > 0 && -0.5*g0 >= g1 && g1 >= -2*g0):
+		return g0*(x-2*x*x+x*x*x) + g1*(-x*x+x*x*x)
+
+	case (g0 < 0 && g1 > -2*g0) || (g0 > 0 && g1 < -2*g0):
+		eta := (g1 + 2*g0) / (g1 - g0)
+		if x <= eta {
+			return g0 * x
+		}
+		return g0*x + (g1-g0)*math.Pow(x-eta, 3)/(3*(1-eta)*(1-eta))
+
+	case (g0 > 0 && g1 < 0 && g1 > -0.5*g0) || (g0 < 0 && g1 > 0 && g1 < -0.5*g0):
+		eta := 3 * g1 / (g1 - g0)
+		if x < eta {
+			return g1*x + (g0-g1)*(eta-math.Pow(eta-x, 3)/(eta*eta))/3
+		}
+		return g1*x + (g0-g1)*eta/3
+
+	default:
+		eta := g1 / (g1 + g0)
+		a := -g0 * g1 / (g0 + g1)
+		if x <= eta {
+			return a*x + (g0-a)*(eta-math.Pow(eta-x, 3)/(eta*eta))/3
+		}
+		return a*x + (g0-a)*eta/3 + (g1-a)*math.Pow(x-eta, 3)/(3*(1-eta)*(1-eta))
+	}
+}
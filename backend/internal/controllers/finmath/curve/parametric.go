@@ -0,0 +1,297 @@
+package curve
+
+import (
+	"backend/internal/controllers/linear"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+type ParametricModel string
+
+const (
+	NelsonSiegel ParametricModel = "nelson_siegel"
+	Svensson     ParametricModel = "svensson"
+)
+
+// ParametricCurve is a Nelson-Siegel or Svensson zero curve. Beta0 is the long-run
+// level, Beta1 the slope (short rate minus level) and Beta2, Beta3 the humps with
+// decay times Tau1, Tau2. Svensson-only fields are zero for Nelson-Siegel.
+type ParametricCurve struct {
+	Model ParametricModel `json:"model"`
+	Beta0 float64         `json:"beta0"`
+	Beta1 float64         `json:"beta1"`
+	Beta2 float64         `json:"beta2"`
+	Beta3 float64         `json:"beta3"`
+	Tau1  float64         `json:"tau1"`
+	Tau2  float64         `json:"tau2"`
+}
+
+// ZeroPoint is a continuously compounded zero rate to maturity
+type ZeroPoint struct {
+	Maturity float64 `json:"maturity"`
+	Rate     float64 `json:"rate"`
+}
+
+// BondYield is a bullet bond issued on a coupon date with its annually compounded
+// yield to maturity. Frequency 0 means a zero-coupon bond.
+type BondYield struct {
+	Maturity  float64 `json:"maturity"`
+	Coupon    float64 `json:"coupon_rate"`
+	Frequency int     `json:"frequency"`
+	Yield     float64 `json:"yield"`
+}
+
+type ParametricFit struct {
+	Curve      ParametricCurve `json:"curve"`
+	Residuals  []float64       `json:"residuals"` // Fitted minus observed rate or yield
+	RMSE       float64         `json:"rmse"`
+	Iterations int             `json:"iterations"`
+	Converged  bool            `json:"converged"`
+}
+
+func loading(t, tau float64) (slope, hump float64) {
+	x := t / tau
+	if x < 1e-8 {
+		return 1, 0
+	}
+	e := math.Exp(-x)
+	slope = (1 - e) / x
+	return slope, slope - e
+}
+
+func (p ParametricCurve) ZeroRate(t float64) float64 {
+	slope, hump := loading(math.Max(t, 0), p.Tau1)
+	r := p.Beta0 + p.Beta1*slope + p.Beta2*hump
+	if p.Model == Svensson {
+		_, hump2 := loading(math.Max(t, 0), p.Tau2)
+		r += p.Beta3 * hump2
+	}
+	return r
+}
+
+func (p ParametricCurve) Discount(t float64) float64 {
+	if t <= 0 {
+		return 1
+	}
+	return math.Exp(-p.ZeroRate(t) * t)
+}
+
+func (p ParametricCurve) ForwardRate(t1, t2 float64) float64 {
+	if t2 < t1 {
+		t1, t2 = t2, t1
+	}
+	if t2-t1 < 1e-9 {
+		x := t1 / p.Tau1
+		f := p.Beta0 + p.Beta1*math.Exp(-x) + p.Beta2*x*math.Exp(-x)
+		if p.Model == Svensson {
+			x2 := t1 / p.Tau2
+			f += p.Beta3 * x2 * math.Exp(-x2)
+		}
+		return f
+	}
+	return (p.ZeroRate(t2)*t2 - p.ZeroRate(t1)*t1) / (t2 - t1)
+}
+
+// Shift moves the level, slope and curvature factors, e.g. for rate scenarios
+func (p ParametricCurve) Shift(level, slope, curvature float64) ParametricCurve {
+	p.Beta0 += level
+	p.Beta1 += slope
+	p.Beta2 += curvature
+	if p.Model == Svensson {
+		p.Beta3 += curvature
+	}
+	return p
+}
+
+func parametricFromX(model ParametricModel, x []float64) ParametricCurve {
+	p := ParametricCurve{Model: model, Beta0: x[0], Beta1: x[1], Beta2: x[2], Tau1: x[3]}
+	if model == Svensson {
+		p.Beta3, p.Tau2 = x[4], x[5]
+	}
+	return p
+}
+
+// modelYield is the annually compounded yield matching the price of the bond's
+// cashflows on the curve
+func (b BondYield) modelYield(c Curve, flows [][2]float64) (float64, error) {
+	if b.Frequency == 0 {
+		return math.Exp(c.ZeroRate(b.Maturity)) - 1, nil
+	}
+	price := 0.0
+	for _, cf := range flows {
+		price += cf[1] * c.Discount(cf[0])
+	}
+	return opt.BrentRoot(func(y float64) float64 {
+		pv := 0.0
+		for _, cf := range flows {
+			pv += cf[1] * math.Pow(1+y, -cf[0])
+		}
+		return pv - price
+	}, -0.5, 2, 1e-12)
+}
+
+// cashflows returns (time, amount) pairs per unit face
+func (b BondYield) cashflows() [][2]float64 {
+	step := 1 / float64(b.Frequency)
+	var out [][2]float64
+	for t := b.Maturity; t > 1e-9; t -= step {
+		out = append(out, [2]float64{t, b.Coupon * step})
+	}
+	out[0][1] += 1
+	return out
+}
+
+// FitParametric fits a Nelson-Siegel or Svensson curve by nonlinear least squares to
+// zero rates or, when bonds are given, to bond yields. Several decay times seed the
+// fit and the best result is kept, since the objective is not convex in tau.
+func FitParametric(model ParametricModel, zeros []ZeroPoint, bonds []BondYield) (ParametricFit, error) {
+	if model != NelsonSiegel && model != Svensson {
+		return ParametricFit{}, errors.New("model must be nelson_siegel or svensson")
+	}
+	if (len(zeros) == 0) == (len(bonds) == 0) {
+		return ParametricFit{}, errors.New("provide either zero rates or bond yields")
+	}
+
+	nParams := 4
+	if model == Svensson {
+		nParams = 6
+	}
+	if len(zeros)+len(bonds) < nParams {
+		return ParametricFit{}, errors.New("not enough points for the number of parameters")
+	}
+
+	// Shortest and longest observations give a fallback starting level and slope
+	var short, long, shortT, longT float64
+	shortT = math.Inf(1)
+	observe := func(t, r float64) {
+		if t < shortT {
+			short, shortT = r, t
+		}
+		if t > longT {
+			long, longT = r, t
+		}
+	}
+	for _, z := range zeros {
+		if z.Maturity <= 0 || !(z.Maturity <= maxMaturity) {
+			return ParametricFit{}, errors.New("maturities must be positive and at most 100 years")
+		}
+		observe(z.Maturity, z.Rate)
+	}
+	bonds = append([]BondYield(nil), bonds...)
+	flows := make([][][2]float64, len(bonds))
+	for i, b := range bonds {
+		if b.Maturity <= 0 || !(b.Maturity <= maxMaturity) || b.Frequency < 0 || b.Frequency > 12 {
+			return ParametricFit{}, errors.New("bond maturities must be positive and at most 100 years, and frequency between 0 and 12")
+		}
+		if b.Frequency == 0 {
+			bonds[i].Coupon = 0
+		} else {
+			flows[i] = b.cashflows()
+		}
+		observe(b.Maturity, b.Yield)
+	}
+
+	residuals := func(x []float64) []float64 {
+		c := parametricFromX(model, x)
+		res := make([]float64, 0, len(zeros)+len(bonds))
+		for _, z := range zeros {
+			res = append(res, c.ZeroRate(z.Maturity)-z.Rate)
+		}
+		for i, b := range bonds {
+			y, err := b.modelYield(c, flows[i])
+			if err != nil {
+				y = b.Yield + 1 // Large but finite so the solver can back off
+			}
+			res = append(res, y-b.Yield)
+		}
+		return res
+	}
+
+	lower := []float64{-1, -1, -1, 0.05, -1, 0.05}[:nParams]
+	upper := []float64{1, 1, 1, 30, 1, 30}[:nParams]
+
+	// Observed rates, treating bond yields as zero rates, seed the linear betas
+	var maturities, rates []float64
+	for _, z := range zeros {
+		maturities, rates = append(maturities, z.Maturity), append(rates, z.Rate)
+	}
+	for _, b := range bonds {
+		maturities, rates = append(maturities, b.Maturity), append(rates, math.Log(1+b.Yield))
+	}
+
+	var best opt.LeastSquaresResult
+	found := false
+	for _, tau1 := range []float64{0.5, 1.5, 3, 6} {
+		for _, tau2 := range []float64{5, 12} {
+			x0 := []float64{long, short - long, 0, tau1, 0, math.Max(tau2, tau1+1)}[:nParams]
+			if betas, err := linearBetas(model, x0[3], x0[nParams-1], maturities, rates); err == nil {
+				copy(x0, betas[:3])
+				if model == Svensson {
+					x0[4] = betas[3]
+				}
+			}
+			fit, err := opt.LevenbergMarquardt(residuals, x0, lower, upper, 1e-14, 500)
+			if err != nil || math.IsNaN(fit.SumSquares) || math.IsInf(fit.SumSquares, 0) {
+				continue
+			}
+			if !found || fit.SumSquares < best.SumSquares {
+				best, found = fit, true
+			}
+			if model == NelsonSiegel {
+				break
+			}
+		}
+	}
+	if !found {
+		return ParametricFit{}, errors.New("curve fit failed")
+	}
+
+	return ParametricFit{
+		Curve:      parametricFromX(model, best.X),
+		Residuals:  best.Residuals,
+		RMSE:       math.Sqrt(best.SumSquares / float64(len(best.Residuals))),
+		Iterations: best.Iterations,
+		Converged:  best.Converged,
+	}, nil
+}
+
+// linearBetas solves for the betas by ordinary least squares with the decay
+// times held fixed
+func linearBetas(model ParametricModel, tau1, tau2 float64, maturities, rates []float64) ([]float64, error) {
+	x := make([][]float64, len(maturities))
+	y := make([][]float64, len(rates))
+	for i, t := range maturities {
+		slope, hump := loading(t, tau1)
+		x[i] = []float64{1, slope, hump}
+		if model == Svensson {
+			_, hump2 := loading(t, tau2)
+			x[i] = append(x[i], hump2)
+		}
+		y[i] = []float64{rates[i]}
+	}
+
+	xt := linear.Transpose(x)
+	xtx, err := linear.Multiply(xt, x)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := linear.Inverse(xtx)
+	if err != nil {
+		return nil, err
+	}
+	xty, err := linear.Multiply(xt, y)
+	if err != nil {
+		return nil, err
+	}
+	beta, err := linear.Multiply(inv, xty)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]float64, len(beta))
+	for i := range beta {
+		out[i] = math.Max(-1, math.Min(1, beta[i][0]))
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package curve
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	maxStoredCurves = 1000
+
+	// curveTTL is how long a curve survives without being loaded
+	curveTTL = 24 * time.Hour
+)
+
+type storedCurve struct {
+	curve    Curve
+	lastUsed time.Time
+}
+
+var (
+	storeMu sync.Mutex
+	store   = map[string]*storedCurve{}
+	now     = time.Now
+)
+
+// Save keeps a curve in memory and returns its ID. Curves do not survive a
+// restart, expire after a day unused, and when the store is full the least
+// recently used curve makes room for the new one.
+func Save(c Curve) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf)
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	t := now()
+	evictExpired(t)
+	if len(store) >= maxStoredCurves {
+		evictOldest()
+	}
+	store[id] = &storedCurve{curve: c, lastUsed: t}
+	return id, nil
+}
+
+func Load(id string) (Curve, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	t := now()
+	s, ok := store[id]
+	if !ok || t.Sub(s.lastUsed) > curveTTL {
+		delete(store, id)
+		return nil, errors.New("unknown curve id: " + id)
+	}
+	s.lastUsed = t
+	return s.curve, nil
+}
+
+func Delete(id string) bool {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	_, ok := store[id]
+	delete(store, id)
+	return ok
+}
+
+func evictExpired(t time.Time) {
+	for id, s := range store {
+		if t.Sub(s.lastUsed) > curveTTL {
+			delete(store, id)
+		}
+	}
+}
+
+func evictOldest() {
+	var oldest string
+	for id, s := range store {
+		if oldest == "" || s.lastUsed.Before(store[oldest].lastUsed) {
+			oldest = id
+		}
+	}
+	delete(store, oldest)
+}
@@ -0,0 +1,521 @@
+package finmath
+
+import (
+	"backend/internal/controllers/dist"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+type BarrierType string
+
+const (
+	DownAndIn  BarrierType = "down-and-in"
+	DownAndOut BarrierType = "down-and-out"
+	UpAndIn    BarrierType = "up-and-in"
+	UpAndOut   BarrierType = "up-and-out"
+)
+
+// Cost of carry b = r - q; all exotics below follow Haug's generalized notation
+func generalizedBlackScholes(isCall bool, S, K, T, r, b, sigma float64) float64 {
+	if T <= 0 {
+		if isCall {
+			return math.Max(S-K, 0)
+		}
+		return math.Max(K-S, 0)
+	}
+
+	sqrtT := math.Sqrt(T)
+	d1 := (math.Log(S/K) + (b+0.5*sigma*sigma)*T) / (sigma * sqrtT)
+	d2 := d1 - sigma*sqrtT
+
+	if isCall {
+		return S*math.Exp((b-r)*T)*dist.StandardNormalCDF(d1) - K*math.Exp(-r*T)*dist.StandardNormalCDF(d2)
+	}
+	return K*math.Exp(-r*T)*dist.StandardNormalCDF(-d2) - S*math.Exp((b-r)*T)*dist.StandardNormalCDF(-d1)
+}
+
+func validateExoticParams(S, K, T, sigma float64) error {
+	if S <= 0 || K <= 0 || T <= 0 || sigma <= 0 {
+		return errors.New("spot, strike, time and volatility must be positive")
+	}
+	return nil
+}
+
+// BarrierOption prices a single barrier option with the Reiner-Rubinstein formulas.
+// The rebate is paid at expiry for knock-ins and at the hit for knock-outs.
+func BarrierOption(barrierType BarrierType, isCall bool, S, K, H, rebate, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+	if H <= 0 {
+		return 0, errors.New("barrier must be positive")
+	}
+	if rebate < 0 {
+		return 0, errors.New("rebate cannot be negative")
+	}
+
+	b := r - q
+	isDown := barrierType == DownAndIn || barrierType == DownAndOut
+	isIn := barrierType == DownAndIn || barrierType == UpAndIn
+	if !isDown && barrierType != UpAndIn && barrierType != UpAndOut {
+		return 0, errors.New("unknown barrier type")
+	}
+
+	// Barrier already breached
+	if (isDown && S <= H) || (!isDown && S >= H) {
+		if isIn {
+			return generalizedBlackScholes(isCall, S, K, T, r, b, sigma), nil
+		}
+		return rebate, nil
+	}
+
+	eta, phi := 1.0, 1.0
+	if !isDown {
+		eta = -1
+	}
+	if !isCall {
+		phi = -1
+	}
+
+	N := dist.StandardNormalCDF
+	sigmaSqrtT := sigma * math.Sqrt(T)
+	mu := (b - sigma*sigma/2) / (sigma * sigma)
+	lambda := math.Sqrt(mu*mu + 2*r/(sigma*sigma))
+	carry := math.Exp((b - r) * T)
+	discount := math.Exp(-r * T)
+	hs := H / S
+
+	x1 := math.Log(S/K)/sigmaSqrtT + (1+mu)*sigmaSqrtT
+	x2 := math.Log(S/H)/sigmaSqrtT + (1+mu)*sigmaSqrtT
+	y1 := math.Log(H*H/(S*K))/sigmaSqrtT + (1+mu)*sigmaSqrtT
+	y2 := math.Log(H/S)/sigmaSqrtT + (1+mu)*sigmaSqrtT
+	z := math.Log(H/S)/sigmaSqrtT + lambda*sigmaSqrtT
+
+	A := phi*S*carry*N(phi*x1) - phi*K*discount*N(phi*x1-phi*sigmaSqrtT)
+	B := phi*S*carry*N(phi*x2) - phi*K*discount*N(phi*x2-phi*sigmaSqrtT)
+	C := phi*S*carry*math.Pow(hs, 2*(mu+1))*N(eta*y1) - phi*K*discount*math.Pow(hs, 2*mu)*N(eta*y1-eta*sigmaSqrtT)
+	D := phi*S*carry*math.Pow(hs, 2*(mu+1))*N(eta*y2) - phi*K*discount*math.Pow(hs, 2*mu)*N(eta*y2-eta*sigmaSqrtT)
+	E := rebate * discount * (N(eta*x2-eta*sigmaSqrtT) - math.Pow(hs, 2*mu)*N(eta*y2-eta*sigmaSqrtT))
+	F := rebate * (math.Pow(hs, mu+lambda)*N(eta*z) + math.Pow(hs, mu-lambda)*N(eta*z-2*eta*lambda*sigmaSqrtT))
+
+	strikeAbove := K > H
+
+	switch {
+	case barrierType == DownAndIn && isCall:
+		if strikeAbove {
+			return C + E, nil
+		}
+		return A - B + D + E, nil
+	case barrierType == UpAndIn && isCall:
+		if strikeAbove {
+			return A + E, nil
+		}
+		return B - C + D + E, nil
+	case barrierType == DownAndIn:
+		if strikeAbove {
+			return B - C + D + E, nil
+		}
+		return A + E, nil
+	case barrierType == UpAndIn:
+		if strikeAbove {
+			return A - B + D + E, nil
+		}
+		return C + E, nil
+	case barrierType == DownAndOut && isCall:
+		if strikeAbove {
+			return A - C + F, nil
+		}
+		return B - D + F, nil
+	case barrierType == UpAndOut && isCall:
+		if strikeAbove {
+			return F, nil
+		}
+		return A - B + C - D + F, nil
+	case barrierType == DownAndOut:
+		if strikeAbove {
+			return A - B + C - D + F, nil
+		}
+		return F, nil
+	default: // up-and-out put
+		if strikeAbove {
+			return B - D + F, nil
+		}
+		return A - C + F, nil
+	}
+}
+
+// DoubleBarrierOption prices a knock-out or knock-in option with flat lower and upper
+// barriers using the Ikeda-Kunitomo series.
+func DoubleBarrierOption(isKnockIn, isCall bool, S, K, L, U, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+	if L <= 0 || U <= L {
+		return 0, errors.New("barriers must satisfy 0 < lower < upper")
+	}
+	if K <= L || K >= U {
+		return 0, errors.New("strike must lie between the barriers")
+	}
+
+	b := r - q
+	vanilla := generalizedBlackScholes(isCall, S, K, T, r, b, sigma)
+
+	if S <= L || S >= U {
+		if isKnockIn {
+			return vanilla, nil
+		}
+		return 0, nil
+	}
+
+	N := dist.StandardNormalCDF
+	sqrtT := math.Sqrt(T)
+	sigmaSqrtT := sigma * sqrtT
+	drift := (b + sigma*sigma/2) * T
+	mu1 := 2*b/(sigma*sigma) + 1
+	mu3 := mu1
+
+	// Truncated series; terms decay like exp(-n^2) so five each way is ample
+	var sum1, sum2 float64
+	for n := -5; n <= 5; n++ {
+		fn := float64(n)
+		u2n := math.Pow(U, 2*fn)
+		l2n := math.Pow(L, 2*fn)
+		l2n2 := math.Pow(L, 2*fn+2)
+		ratio := math.Pow(U, fn) / math.Pow(L, fn)
+		reflect := math.Pow(L, fn+1) / (math.Pow(U, fn) * S)
+
+		if isCall {
+			d1 := (math.Log(S*u2n/(K*l2n)) + drift) / sigmaSqrtT
+			d2 := (math.Log(S*u2n/(U*l2n)) + drift) / sigmaSqrtT
+			d3 := (math.Log(l2n2/(K*S*u2n)) + drift) / sigmaSqrtT
+			d4 := (math.Log(l2n2/(U*S*u2n)) + drift) / sigmaSqrtT
+
+			sum1 += math.Pow(ratio, mu1)*(N(d1)-N(d2)) - math.Pow(reflect, mu3)*(N(d3)-N(d4))
+			sum2 += math.Pow(ratio, mu1-2)*(N(d1-sigmaSqrtT)-N(d2-sigmaSqrtT)) -
+				math.Pow(reflect, mu3-2)*(N(d3-sigmaSqrtT)-N(d4-sigmaSqrtT))
+		} else {
+			y1 := (math.Log(S*u2n/(L*l2n)) + drift) / sigmaSqrtT
+			y2 := (math.Log(S*u2n/(K*l2n)) + drift) / sigmaSqrtT
+			y3 := (math.Log(l2n2/(L*S*u2n)) + drift) / sigmaSqrtT
+			y4 := (math.Log(l2n2/(K*S*u2n)) + drift) / sigmaSqrtT
+
+			sum1 += math.Pow(ratio, mu1-2)*(N(y1-sigmaSqrtT)-N(y2-sigmaSqrtT)) -
+				math.Pow(reflect, mu3-2)*(N(y3-sigmaSqrtT)-N(y4-sigmaSqrtT))
+			sum2 += math.Pow(ratio, mu1)*(N(y1)-N(y2)) - math.Pow(reflect, mu3)*(N(y3)-N(y4))
+		}
+	}
+
+	var knockOut float64
+	if isCall {
+		knockOut = S*math.Exp((b-r)*T)*sum1 - K*math.Exp(-r*T)*sum2
+	} else {
+		knockOut = K*math.Exp(-r*T)*sum1 - S*math.Exp((b-r)*T)*sum2
+	}
+	knockOut = math.Max(knockOut, 0)
+
+	if isKnockIn {
+		return vanilla - knockOut, nil
+	}
+	return knockOut, nil
+}
+
+// CashOrNothing pays a fixed cash amount if the option finishes in the money
+func CashOrNothing(isCall bool, S, K, cash, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+
+	b := r - q
+	d := (math.Log(S/K) + (b-sigma*sigma/2)*T) / (sigma * math.Sqrt(T))
+	if !isCall {
+		d = -d
+	}
+	return cash * math.Exp(-r*T) * dist.StandardNormalCDF(d), nil
+}
+
+// AssetOrNothing pays one unit of the underlying if the option finishes in the money
+func AssetOrNothing(isCall bool, S, K, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+
+	b := r - q
+	d := (math.Log(S/K) + (b+sigma*sigma/2)*T) / (sigma * math.Sqrt(T))
+	if !isCall {
+		d = -d
+	}
+	return S * math.Exp((b-r)*T) * dist.StandardNormalCDF(d), nil
+}
+
+// GeometricAsian prices a continuously sampled geometric average rate option (Kemna-Vorst)
+func GeometricAsian(isCall bool, S, K, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+
+	b := r - q
+	sigmaA := sigma / math.Sqrt(3)
+	bA := 0.5 * (b - sigma*sigma/6)
+	return generalizedBlackScholes(isCall, S, K, T, r, bA, sigmaA), nil
+}
+
+// ArithmeticAsian approximates an arithmetic average rate option with Turnbull-Wakeman
+// moment matching. Averaging runs from avgStart to expiry T.
+func ArithmeticAsian(isCall bool, S, K, T, avgStart, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+	if avgStart < 0 || avgStart >= T {
+		return 0, errors.New("averaging must start before expiry")
+	}
+
+	b := r - q
+	sigma2 := sigma * sigma
+	period := T - avgStart
+
+	var m1, m2 float64
+	if math.Abs(b) < 1e-10 {
+		m1 = 1
+		m2 = 2*math.Exp(sigma2*T)/(sigma2*sigma2*period*period) -
+			2*math.Exp(sigma2*avgStart)*(1+sigma2*period)/(sigma2*sigma2*period*period)
+	} else {
+		m1 = (math.Exp(b*T) - math.Exp(b*avgStart)) / (b * period)
+		m2 = 2*math.Exp((2*b+sigma2)*T)/((b+sigma2)*(2*b+sigma2)*period*period) +
+			2*math.Exp((2*b+sigma2)*avgStart)/(b*period*period)*
+				(1/(2*b+sigma2)-math.Exp(b*period)/(b+sigma2))
+	}
+
+	bA := math.Log(m1) / T
+	varA := math.Log(m2)/T - 2*bA
+	if varA <= 0 {
+		return 0, errors.New("moment matching produced a non-positive variance")
+	}
+
+	return generalizedBlackScholes(isCall, S, K, T, r, bA, math.Sqrt(varA)), nil
+}
+
+// carryForLookback avoids the removable singularity at zero cost of carry
+func carryForLookback(b float64) float64 {
+	if math.Abs(b) < 1e-6 {
+		if b < 0 {
+			return -1e-6
+		}
+		return 1e-6
+	}
+	return b
+}
+
+// FloatingLookback prices a floating strike lookback (Goldman-Sosin-Gatto).
+// extreme is the observed minimum for calls and maximum for puts.
+func FloatingLookback(isCall bool, S, extreme, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, extreme, T, sigma); err != nil {
+		return 0, err
+	}
+	if isCall && extreme > S {
+		return 0, errors.New("observed minimum cannot exceed spot")
+	}
+	if !isCall && extreme < S {
+		return 0, errors.New("observed maximum cannot be below spot")
+	}
+
+	N := dist.StandardNormalCDF
+	b := carryForLookback(r - q)
+	sqrtT := math.Sqrt(T)
+	sigma2 := sigma * sigma
+	a1 := (math.Log(S/extreme) + (b+sigma2/2)*T) / (sigma * sqrtT)
+	a2 := a1 - sigma*sqrtT
+	shift := 2 * b * sqrtT / sigma
+	power := math.Pow(S/extreme, -2*b/sigma2)
+	scale := S * math.Exp(-r*T) * sigma2 / (2 * b)
+
+	if isCall {
+		return S*math.Exp((b-r)*T)*N(a1) - extreme*math.Exp(-r*T)*N(a2) +
+			scale*(power*N(-a1+shift)-math.Exp(b*T)*N(-a1)), nil
+	}
+	return extreme*math.Exp(-r*T)*N(-a2) - S*math.Exp((b-r)*T)*N(-a1) +
+		scale*(-power*N(a1-shift)+math.Exp(b*T)*N(a1)), nil
+}
+
+// FixedLookback prices a fixed strike lookback (Conze-Viswanathan).
+// extreme is the observed maximum for calls and minimum for puts.
+func FixedLookback(isCall bool, S, K, extreme, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+	if extreme <= 0 {
+		return 0, errors.New("observed extreme must be positive")
+	}
+	if isCall && extreme < S {
+		return 0, errors.New("observed maximum cannot be below spot")
+	}
+	if !isCall && extreme > S {
+		return 0, errors.New("observed minimum cannot exceed spot")
+	}
+
+	N := dist.StandardNormalCDF
+	b := carryForLookback(r - q)
+	sqrtT := math.Sqrt(T)
+	sigma2 := sigma * sigma
+	shift := 2 * b * sqrtT / sigma
+	scale := S * math.Exp(-r*T) * sigma2 / (2 * b)
+	d := func(level float64) float64 {
+		return (math.Log(S/level) + (b+sigma2/2)*T) / (sigma * sqrtT)
+	}
+
+	if isCall {
+		if K > extreme {
+			d1 := d(K)
+			d2 := d1 - sigma*sqrtT
+			return S*math.Exp((b-r)*T)*N(d1) - K*math.Exp(-r*T)*N(d2) +
+				scale*(-math.Pow(S/K, -2*b/sigma2)*N(d1-shift)+math.Exp(b*T)*N(d1)), nil
+		}
+		e1 := d(extreme)
+		e2 := e1 - sigma*sqrtT
+		return math.Exp(-r*T)*(extreme-K) + S*math.Exp((b-r)*T)*N(e1) - extreme*math.Exp(-r*T)*N(e2) +
+			scale*(-math.Pow(S/extreme, -2*b/sigma2)*N(e1-shift)+math.Exp(b*T)*N(e1)), nil
+	}
+
+	if K < extreme {
+		d1 := d(K)
+		d2 := d1 - sigma*sqrtT
+		return K*math.Exp(-r*T)*N(-d2) - S*math.Exp((b-r)*T)*N(-d1) +
+			scale*(math.Pow(S/K, -2*b/sigma2)*N(-d1+shift)-math.Exp(b*T)*N(-d1)), nil
+	}
+	f1 := d(extreme)
+	f2 := f1 - sigma*sqrtT
+	return math.Exp(-r*T)*(K-extreme) - S*math.Exp((b-r)*T)*N(-f1) + extreme*math.Exp(-r*T)*N(-f2) +
+		scale*(math.Pow(S/extreme, -2*b/sigma2)*N(-f1+shift)-math.Exp(b*T)*N(-f1)), nil
+}
+
+// criticalSpot solves g(I) = 0 over I > 0 for a g that is monotone in the spot
+func criticalSpot(g func(float64) float64, S float64) (float64, error) {
+	lo, hi := S/2, S*2
+	for i := 0; i < 60 && g(lo)*g(hi) > 0; i++ {
+		lo /= 2
+		hi *= 2
+	}
+	if g(lo)*g(hi) > 0 {
+		return 0, errors.New("failed to bracket critical spot price")
+	}
+	return opt.BrentRoot(g, lo, hi, 1e-10*S)
+}
+
+// CompoundOption prices an option on an option (Geske). The compound option with
+// strike K2 expires at T1 and delivers an option with strike K1 expiring at T2.
+// r1 and r2 are the zero rates to T1 and T2; pass the same rate twice for a
+// flat curve.
+func CompoundOption(outerCall, innerCall bool, S, K1, K2, T1, T2, r1, r2, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K1, T2, sigma); err != nil {
+		return 0, err
+	}
+	if K2 <= 0 {
+		return 0, errors.New("compound strike must be positive")
+	}
+	if T1 <= 0 || T1 >= T2 {
+		return 0, errors.New("compound expiry must be positive and before the underlying expiry")
+	}
+
+	// The delivered option is valued at T1 with the forward rate to T2
+	f := forwardRate(r1, T1, r2, T2)
+
+	// A put is worth at most its discounted strike, so when that is below K2 a
+	// call on it is never exercised and a put on it always is
+	if !innerCall && K2 >= K1*math.Exp(-f*(T2-T1)) {
+		if outerCall {
+			return 0, nil
+		}
+		return K2*math.Exp(-r1*T1) - generalizedBlackScholes(false, S, K1, T2, r2, r2-q, sigma), nil
+	}
+
+	I, err := criticalSpot(func(x float64) float64 {
+		return generalizedBlackScholes(innerCall, x, K1, T2-T1, f, f-q, sigma) - K2
+	}, S)
+	if err != nil {
+		return 0, err
+	}
+
+	N := dist.StandardNormalCDF
+	M := dist.BivariateNormalCDF
+	sigma2 := sigma * sigma
+	y1 := (math.Log(S/I) + (r1-q+sigma2/2)*T1) / (sigma * math.Sqrt(T1))
+	y2 := y1 - sigma*math.Sqrt(T1)
+	z1 := (math.Log(S/K1) + (r2-q+sigma2/2)*T2) / (sigma * math.Sqrt(T2))
+	z2 := z1 - sigma*math.Sqrt(T2)
+	rho := math.Sqrt(T1 / T2)
+	asset := S * math.Exp(-q*T2)
+	strike := K1 * math.Exp(-r2*T2)
+	premium := K2 * math.Exp(-r1*T1)
+
+	switch {
+	case outerCall && innerCall:
+		return asset*M(z1, y1, rho) - strike*M(z2, y2, rho) - premium*N(y2), nil
+	case !outerCall && innerCall:
+		return strike*M(z2, -y2, -rho) - asset*M(z1, -y1, -rho) + premium*N(-y2), nil
+	case outerCall && !innerCall:
+		return strike*M(-z2, -y2, rho) - asset*M(-z1, -y1, rho) - premium*N(-y2), nil
+	default:
+		return asset*M(-z1, y1, -rho) - strike*M(-z2, y2, -rho) + premium*N(y2), nil
+	}
+}
+
+// SimpleChooser prices an option whose holder picks call or put at time t, both
+// struck at K and expiring at T (Rubinstein).
+func SimpleChooser(S, K, t, T, r, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, K, T, sigma); err != nil {
+		return 0, err
+	}
+	if t <= 0 || t >= T {
+		return 0, errors.New("choice time must be positive and before expiry")
+	}
+
+	N := dist.StandardNormalCDF
+	b := r - q
+	d := (math.Log(S/K) + (b+sigma*sigma/2)*T) / (sigma * math.Sqrt(T))
+	y := (math.Log(S/K) + b*T + sigma*sigma*t/2) / (sigma * math.Sqrt(t))
+
+	return S*math.Exp((b-r)*T)*N(d) - K*math.Exp(-r*T)*N(d-sigma*math.Sqrt(T)) -
+		S*math.Exp((b-r)*T)*N(-y) + K*math.Exp(-r*T)*N(-y+sigma*math.Sqrt(t)), nil
+}
+
+// ComplexChooser prices a chooser whose call and put legs have their own strikes
+// and expiries (Rubinstein). rt, rc and rp are the zero rates to the choice time
+// and to the call and put expiries.
+func ComplexChooser(S, Kc, Kp, t, Tc, Tp, rt, rc, rp, q, sigma float64) (float64, error) {
+	if err := validateExoticParams(S, Kc, Tc, sigma); err != nil {
+		return 0, err
+	}
+	if Kp <= 0 {
+		return 0, errors.New("put strike must be positive")
+	}
+	if t <= 0 || t >= Tc || t >= Tp {
+		return 0, errors.New("choice time must be positive and before both expiries")
+	}
+
+	fc, fp := forwardRate(rt, t, rc, Tc), forwardRate(rt, t, rp, Tp)
+	I, err := criticalSpot(func(x float64) float64 {
+		return generalizedBlackScholes(true, x, Kc, Tc-t, fc, fc-q, sigma) -
+			generalizedBlackScholes(false, x, Kp, Tp-t, fp, fp-q, sigma)
+	}, S)
+	if err != nil {
+		return 0, err
+	}
+
+	M := dist.BivariateNormalCDF
+	sigma2 := sigma * sigma
+	d1 := (math.Log(S/I) + (rt-q+sigma2/2)*t) / (sigma * math.Sqrt(t))
+	d2 := d1 - sigma*math.Sqrt(t)
+	y1 := (math.Log(S/Kc) + (rc-q+sigma2/2)*Tc) / (sigma * math.Sqrt(Tc))
+	y2 := (math.Log(S/Kp) + (rp-q+sigma2/2)*Tp) / (sigma * math.Sqrt(Tp))
+	rho1 := math.Sqrt(t / Tc)
+	rho2 := math.Sqrt(t / Tp)
+
+	return S*math.Exp(-q*Tc)*M(d1, y1, rho1) - Kc*math.Exp(-rc*Tc)*M(d2, y1-sigma*math.Sqrt(Tc), rho1) -
+		S*math.Exp(-q*Tp)*M(-d1, -y2, rho2) + Kp*math.Exp(-rp*Tp)*M(-d2, -y2+sigma*math.Sqrt(Tp), rho2), nil
+}
+
+// forwardRate is the continuously compounded rate from T1 to T2 implied by zero
+// rates r1 and r2
+func forwardRate(r1, T1, r2, T2 float64) float64 {
+	return (r2*T2 - r1*T1) / (T2 - T1)
+}
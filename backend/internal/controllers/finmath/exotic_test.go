@@ -0,0 +1,125 @@
+package finmath
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference values are from Haug, The Complete Guide to Option Pricing Formulas
+// (2nd ed.), quoted to four decimals. Haug's cost of carry b is r - q here.
+
+func TestBarrierOption(t *testing.T) {
+	// Table 4-13: S = 100, rebate 3, T = 0.5, r = 0.08, b = 0.04, σ = 0.25
+	tests := []struct {
+		barrier BarrierType
+		isCall  bool
+		K, H    float64
+		want    float64
+	}{
+		{DownAndOut, true, 90, 95, 9.0246},
+		{DownAndOut, true, 100, 95, 6.7924},
+		{DownAndOut, true, 110, 95, 4.8759},
+		{DownAndIn, true, 90, 95, 7.7627},
+		{DownAndIn, true, 100, 95, 4.0109},
+		{DownAndIn, true, 110, 95, 2.0576},
+		{UpAndOut, true, 90, 105, 2.6789},
+		{UpAndIn, true, 90, 105, 14.1112},
+		{DownAndOut, false, 90, 95, 2.2798},
+		{DownAndOut, false, 100, 95, 2.2947},
+		{DownAndOut, false, 110, 95, 2.6252},
+	}
+	for _, tt := range tests {
+		got, err := BarrierOption(tt.barrier, tt.isCall, 100, tt.K, tt.H, 3, 0.5, 0.08, 0.04, 0.25)
+		if err != nil || math.Abs(got-tt.want) > 5e-4 {
+			t.Errorf("%s call=%v K=%g H=%g: got %.4f (%v), want %.4f", tt.barrier, tt.isCall, tt.K, tt.H, got, err, tt.want)
+		}
+	}
+}
+
+func TestClosedFormExotics(t *testing.T) {
+	tests := []struct {
+		name  string
+		price func() (float64, error)
+		want  float64
+	}{
+		// 4.19.2: S = 100, K = 80, cash 10, T = 0.75, r = 0.06, b = 0, σ = 0.35
+		{"cash-or-nothing put", func() (float64, error) {
+			return CashOrNothing(false, 100, 80, 10, 0.75, 0.06, 0.06, 0.35)
+		}, 2.6710},
+		// 4.19.3: S = 70, K = 65, T = 0.5, r = 0.07, b = 0.02, σ = 0.27
+		{"asset-or-nothing put", func() (float64, error) {
+			return AssetOrNothing(false, 70, 65, 0.5, 0.07, 0.05, 0.27)
+		}, 20.2069},
+		// 4.20.1: S = 80, K = 85, T = 0.25, r = 0.05, b = 0.08, σ = 0.2
+		{"geometric asian put", func() (float64, error) {
+			return GeometricAsian(false, 80, 85, 0.25, 0.05, -0.03, 0.2)
+		}, 4.6922},
+		// 4.15.1: S = 120, S_min = 100, T = 0.5, r = 0.1, b = 0.04, σ = 0.3
+		{"floating lookback call", func() (float64, error) {
+			return FloatingLookback(true, 120, 100, 0.5, 0.1, 0.06, 0.3)
+		}, 25.3533},
+		// 4.6: S = 50, K = 50, t = 0.25, T = 0.5, r = 0.08, b = 0.08, σ = 0.25
+		{"simple chooser", func() (float64, error) {
+			return SimpleChooser(50, 50, 0.25, 0.5, 0.08, 0, 0.25)
+		}, 6.1071},
+		// 4.7: S = 50, Kc = 55, Kp = 48, t = 0.25, Tc = 0.5, Tp = 0.5833, r = 0.1, b = 0.05, σ = 0.35
+		{"complex chooser", func() (float64, error) {
+			return ComplexChooser(50, 55, 48, 0.25, 0.5, 0.583333, 0.1, 0.1, 0.1, 0.05, 0.35)
+		}, 6.0508},
+		// 4.8: put on call, S = 500, K1 = 520, K2 = 50, T1 = 0.25, T2 = 0.5, r = 0.08, b = 0.05, σ = 0.35
+		{"put on call", func() (float64, error) {
+			return CompoundOption(false, true, 500, 520, 50, 0.25, 0.5, 0.08, 0.08, 0.03, 0.35)
+		}, 21.1965},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.price()
+			if err != nil || math.Abs(got-tt.want) > 5e-4 {
+				t.Errorf("got %.4f (%v), want %.4f", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompoundOptionParity(t *testing.T) {
+	// Call-on-X minus put-on-X is X less the discounted compound strike, with a
+	// term structure as well as a flat rate
+	for _, inner := range []bool{true, false} {
+		for _, rates := range [][2]float64{{0.05, 0.05}, {0.02, 0.06}} {
+			S, K1, K2, T1, T2, q, v := 100.0, 100.0, 4.0, 0.5, 1.5, 0.01, 0.3
+			call, err1 := CompoundOption(true, inner, S, K1, K2, T1, T2, rates[0], rates[1], q, v)
+			put, err2 := CompoundOption(false, inner, S, K1, K2, T1, T2, rates[0], rates[1], q, v)
+			if err1 != nil || err2 != nil {
+				t.Fatalf("inner call=%v: %v %v", inner, err1, err2)
+			}
+			underlying := generalizedBlackScholes(inner, S, K1, T2, rates[1], rates[1]-q, v)
+			want := underlying - K2*math.Exp(-rates[0]*T1)
+			if math.Abs(call-put-want) > 1e-6 {
+				t.Errorf("inner call=%v rates=%v: call-put = %.8f, want %.8f", inner, rates, call-put, want)
+			}
+		}
+	}
+}
+
+func TestCompoundOptionOnDeepPut(t *testing.T) {
+	// K2 above the most the put can be worth at T1: the call is never exercised
+	// and the put always is
+	S, K1, K2, T1, T2, r, v := 100.0, 10.0, 12.0, 0.5, 1.0, 0.05, 0.3
+	call, err := CompoundOption(true, false, S, K1, K2, T1, T2, r, r, 0, v)
+	if err != nil || call != 0 {
+		t.Errorf("call on put: got %v (%v), want 0", call, err)
+	}
+	put, err := CompoundOption(false, false, S, K1, K2, T1, T2, r, r, 0, v)
+	want := K2*math.Exp(-r*T1) - generalizedBlackScholes(false, S, K1, T2, r, r, v)
+	if err != nil || math.Abs(put-want) > 1e-12 {
+		t.Errorf("put on put: got %v (%v), want %v", put, err, want)
+	}
+}
+
+func TestSimpleChooserIsComplexChooserWithEqualTerms(t *testing.T) {
+	simple, err1 := SimpleChooser(100, 100, 0.25, 1, 0.05, 0.02, 0.2)
+	complex, err2 := ComplexChooser(100, 100, 100, 0.25, 1, 1, 0.05, 0.05, 0.05, 0.02, 0.2)
+	if err1 != nil || err2 != nil || math.Abs(simple-complex) > 1e-6 {
+		t.Errorf("simple %.6f (%v), complex %.6f (%v)", simple, err1, complex, err2)
+	}
+}
@@ -0,0 +1,133 @@
+package fx
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Quote prices one unit of Base in Quote currency, e.g. EUR/USD
+type Quote struct {
+	Pair string  `json:"pair"`
+	Bid  float64 `json:"bid"`
+	Ask  float64 `json:"ask"`
+}
+
+func (q Quote) currencies() (string, string, error) {
+	pair := strings.ToUpper(strings.TrimSpace(q.Pair))
+	if base, quote, ok := strings.Cut(pair, "/"); ok && base != "" && quote != "" {
+		return base, quote, nil
+	}
+	if len(pair) == 6 {
+		return pair[:3], pair[3:], nil
+	}
+	return "", "", errors.New("pairs must look like EUR/USD or EURUSD: " + q.Pair)
+}
+
+// Market holds the best executable conversion between every quoted pair:
+// rates[a][b] is the amount of b received for one unit of a
+type Market struct {
+	rates map[string]map[string]float64
+}
+
+func NewMarket(quotes []Quote) (*Market, error) {
+	m := &Market{rates: map[string]map[string]float64{}}
+	set := func(from, to string, rate float64) {
+		if m.rates[from] == nil {
+			m.rates[from] = map[string]float64{}
+		}
+		if rate > m.rates[from][to] {
+			m.rates[from][to] = rate
+		}
+	}
+	for _, q := range quotes {
+		base, quote, err := q.currencies()
+		if err != nil {
+			return nil, err
+		}
+		ask := q.Ask
+		if ask == 0 {
+			ask = q.Bid
+		}
+		if base == quote || q.Bid <= 0 || ask < q.Bid {
+			return nil, errors.New("quotes need distinct currencies and 0 < bid <= ask: " + q.Pair)
+		}
+		set(base, quote, q.Bid)
+		set(quote, base, 1/ask)
+	}
+	return m, nil
+}
+
+func (m *Market) Currencies() []string {
+	out := make([]string, 0, len(m.rates))
+	for c := range m.rates {
+		out = append(out, c)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// convert is the best rate from a to b directly or through one other currency
+func (m *Market) convert(a, b string) (float64, string) {
+	best, via := m.rates[a][b], ""
+	for c, r1 := range m.rates[a] {
+		if c == b {
+			continue
+		}
+		if r2 := m.rates[c][b]; r1*r2 > best {
+			best, via = r1*r2, c
+		}
+	}
+	return best, via
+}
+
+type CrossRate struct {
+	Pair   string  `json:"pair"`
+	Bid    float64 `json:"bid"`
+	Ask    float64 `json:"ask"`
+	BidVia string  `json:"bid_via,omitempty"` // Intermediate currency, empty when quoted directly
+	AskVia string  `json:"ask_via,omitempty"`
+}
+
+// Cross derives the executable bid and ask for base/quote, routing through at
+// most one intermediate currency
+func (m *Market) Cross(base, quote string) (CrossRate, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	bid, bidVia := m.convert(base, quote)
+	inverse, askVia := m.convert(quote, base)
+	if bid == 0 || inverse == 0 {
+		return CrossRate{}, errors.New("no route between " + base + " and " + quote)
+	}
+	return CrossRate{Pair: base + "/" + quote, Bid: bid, Ask: 1 / inverse, BidVia: bidVia, AskVia: askVia}, nil
+}
+
+type TriangularArbitrage struct {
+	Path   []string `json:"path"`
+	Profit float64  `json:"profit"` // Fractional gain per unit of the starting currency
+}
+
+// TriangularArbitrage finds every three-currency cycle of direct quotes that
+// returns more than 1 + minProfit, each reported once starting from its
+// alphabetically first currency
+func (m *Market) TriangularArbitrage(minProfit float64) []TriangularArbitrage {
+	out := []TriangularArbitrage{}
+	currencies := m.Currencies()
+	for _, a := range currencies {
+		for b, ab := range m.rates[a] {
+			for c, bc := range m.rates[b] {
+				if c == a || b < a || c < a {
+					continue
+				}
+				ca := m.rates[c][a]
+				if ca == 0 {
+					continue
+				}
+				if p := ab*bc*ca - 1; p > minProfit {
+					out = append(out, TriangularArbitrage{Path: []string{a, b, c, a}, Profit: p})
+				}
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Profit > out[j].Profit })
+	return out
+}
@@ -0,0 +1,66 @@
+package fx
+
+import (
+	"errors"
+	"math"
+)
+
+type Compounding string
+
+const (
+	Continuous Compounding = "continuous"
+	Simple     Compounding = "simple" // Money-market rates over T years, for deposits up to a year
+)
+
+type ForwardResult struct {
+	Forward float64 `json:"forward"`
+	Points  float64 `json:"points"` // (F - S) in pips
+}
+
+func growth(rate, T float64, compounding Compounding) (float64, error) {
+	switch compounding {
+	case Continuous, "":
+		return math.Exp(rate * T), nil
+	case Simple:
+		if 1+rate*T <= 0 {
+			return 0, errors.New("simple rates must keep 1 + rT positive")
+		}
+		return 1 + rate*T, nil
+	}
+	return 0, errors.New("compounding must be continuous or simple")
+}
+
+// Forward applies covered interest parity, F = S·(growth in domestic)/(growth in
+// foreign), for a pair quoted as domestic units per foreign unit. pip is the
+// quote's pip size, typically 0.0001 or 0.01 for yen pairs.
+func Forward(spot, rd, rf, T, pip float64, compounding Compounding) (ForwardResult, error) {
+	if spot <= 0 || T < 0 || pip <= 0 {
+		return ForwardResult{}, errors.New("spot and pip size must be positive and T non-negative")
+	}
+	gd, err := growth(rd, T, compounding)
+	if err != nil {
+		return ForwardResult{}, err
+	}
+	gf, err := growth(rf, T, compounding)
+	if err != nil {
+		return ForwardResult{}, err
+	}
+	F := spot * gd / gf
+	return ForwardResult{Forward: F, Points: (F - spot) / pip}, nil
+}
+
+// ImpliedForeignRate backs the foreign rate out of a quoted forward
+func ImpliedForeignRate(spot, forward, rd, T float64, compounding Compounding) (float64, error) {
+	if spot <= 0 || forward <= 0 || T <= 0 {
+		return 0, errors.New("spot, forward and T must be positive")
+	}
+	gd, err := growth(rd, T, compounding)
+	if err != nil {
+		return 0, err
+	}
+	gf := gd * spot / forward
+	if compounding == Simple {
+		return (gf - 1) / T, nil
+	}
+	return math.Log(gf) / T, nil
+}
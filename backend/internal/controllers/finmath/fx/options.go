@@ -0,0 +1,174 @@
+package fx
+
+import (
+	"backend/internal/controllers/dist"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+// DeltaConvention is how an FX option's delta is quoted. Premium-adjusted deltas
+// apply when the premium is paid in the foreign (base) currency, as for USD/JPY.
+type DeltaConvention string
+
+const (
+	SpotDelta      DeltaConvention = "spot"
+	ForwardDelta   DeltaConvention = "forward"
+	SpotDeltaPA    DeltaConvention = "spot_pa"
+	ForwardDeltaPA DeltaConvention = "forward_pa"
+)
+
+// At-the-money conventions accepted by ATMStrike
+const (
+	ATMSpot         = "spot"
+	ATMForward      = "atmf"
+	ATMDeltaNeutral = "dns" // Delta-neutral straddle
+)
+
+var DeltaConventions = []DeltaConvention{SpotDelta, ForwardDelta, SpotDeltaPA, ForwardDeltaPA}
+
+func (c DeltaConvention) premiumAdjusted() bool { return c == SpotDeltaPA || c == ForwardDeltaPA }
+func (c DeltaConvention) spot() bool            { return c == SpotDelta || c == SpotDeltaPA }
+
+func (c DeltaConvention) validate() error {
+	switch c {
+	case SpotDelta, ForwardDelta, SpotDeltaPA, ForwardDeltaPA:
+		return nil
+	}
+	return errors.New("delta convention must be spot, forward, spot_pa or forward_pa")
+}
+
+func validateInputs(S, T, sigma float64) error {
+	if S <= 0 || T <= 0 || sigma <= 0 {
+		return errors.New("spot, expiry and volatility must be positive")
+	}
+	return nil
+}
+
+func phi(isCall bool) float64 {
+	if isCall {
+		return 1
+	}
+	return -1
+}
+
+// GarmanKohlhagen prices a European option on one unit of foreign currency in
+// domestic currency, with continuously compounded rates
+func GarmanKohlhagen(isCall bool, S, K, T, rd, rf, sigma float64) (float64, error) {
+	if err := validateInputs(S, T, sigma); err != nil || K <= 0 {
+		return 0, errors.New("spot, strike, expiry and volatility must be positive")
+	}
+	s := sigma * math.Sqrt(T)
+	F := S * math.Exp((rd-rf)*T)
+	d1 := (math.Log(F/K) + s*s/2) / s
+	w := phi(isCall)
+	return w * math.Exp(-rd*T) * (F*dist.StandardNormalCDF(w*d1) - K*dist.StandardNormalCDF(w*(d1-s))), nil
+}
+
+// Delta is the option's delta under the given quoting convention: forward delta
+// is φN(φd1), premium-adjusted forward delta φ(K/F)N(φd2), and the spot versions
+// carry an extra e^{-rf T}
+func Delta(isCall bool, S, K, T, rd, rf, sigma float64, conv DeltaConvention) (float64, error) {
+	if err := validateInputs(S, T, sigma); err != nil || K <= 0 {
+		return 0, errors.New("spot, strike, expiry and volatility must be positive")
+	}
+	if err := conv.validate(); err != nil {
+		return 0, err
+	}
+	s := sigma * math.Sqrt(T)
+	F := S * math.Exp((rd-rf)*T)
+	d1 := (math.Log(F/K) + s*s/2) / s
+	w := phi(isCall)
+
+	delta := w * dist.StandardNormalCDF(w*d1)
+	if conv.premiumAdjusted() {
+		delta = w * K / F * dist.StandardNormalCDF(w*(d1-s))
+	}
+	if conv.spot() {
+		delta *= math.Exp(-rf * T)
+	}
+	return delta, nil
+}
+
+// StrikeFromDelta inverts Delta. Premium-adjusted call deltas rise and then fall
+// with the strike, so the root is taken on the out-of-the-money side of the peak,
+// the strike market quotes refer to.
+func StrikeFromDelta(isCall bool, delta, S, T, rd, rf, sigma float64, conv DeltaConvention) (float64, error) {
+	if err := validateInputs(S, T, sigma); err != nil {
+		return 0, err
+	}
+	if err := conv.validate(); err != nil {
+		return 0, err
+	}
+	w := phi(isCall)
+	target := delta * w
+	if conv.spot() {
+		target *= math.Exp(rf * T)
+	}
+	if target <= 0 {
+		return 0, errors.New("call deltas must be positive and put deltas negative")
+	}
+
+	s := sigma * math.Sqrt(T)
+	F := S * math.Exp((rd-rf)*T)
+	if !conv.premiumAdjusted() {
+		if target >= 1 {
+			return 0, errors.New("forward delta must be below 1 in magnitude")
+		}
+		// N(φd1) is monotone in d1, and K = F exp(-d1 s + s²/2)
+		d1, err := opt.BrentRoot(func(d float64) float64 { return dist.StandardNormalCDF(w*d) - target }, -40, 40, 1e-12)
+		if err != nil {
+			return 0, err
+		}
+		return F * math.Exp(-d1*s+s*s/2), nil
+	}
+
+	// With K/F = exp(-d2 s - s²/2), the adjusted delta is a function of d2 alone
+	adjusted := func(d2 float64) float64 { return math.Exp(-d2*s-s*s/2) * dist.StandardNormalCDF(w*d2) }
+	lo, hi := -40.0, 40.0
+	if isCall {
+		// Peak where s N(d2) = n(d2); it lies above -s
+		peak, err := opt.BrentRoot(func(d float64) float64 {
+			return s*dist.StandardNormalCDF(d) - dist.NormalPDF(d, 0, 1)
+		}, -s, 40, 1e-12)
+		if err != nil {
+			return 0, err
+		}
+		if max := adjusted(peak); target > max {
+			return 0, errors.New("premium-adjusted call delta exceeds its maximum for this volatility and expiry")
+		}
+		hi = peak
+	} else {
+		lo = math.Max(lo, -700/math.Max(s, 1e-9)) // Keep exp(-d2 s) finite
+	}
+	d2, err := opt.BrentRoot(func(d float64) float64 { return adjusted(d) - target }, lo, hi, 1e-12)
+	if err != nil {
+		return 0, err
+	}
+	return F * math.Exp(-d2*s-s*s/2), nil
+}
+
+// ATMStrike is the at-the-money strike: the spot, the forward, or the delta-neutral
+// straddle strike where call and put deltas cancel, F e^{σ²T/2}, or F e^{-σ²T/2}
+// when deltas are premium-adjusted
+func ATMStrike(kind string, S, T, rd, rf, sigma float64, conv DeltaConvention) (float64, error) {
+	if err := validateInputs(S, T, sigma); err != nil {
+		return 0, err
+	}
+	F := S * math.Exp((rd-rf)*T)
+	switch kind {
+	case ATMSpot:
+		return S, nil
+	case ATMForward, "":
+		return F, nil
+	case ATMDeltaNeutral:
+		if err := conv.validate(); err != nil {
+			return 0, err
+		}
+		if conv.premiumAdjusted() {
+			return F * math.Exp(-sigma*sigma*T/2), nil
+		}
+		return F * math.Exp(sigma*sigma*T/2), nil
+	}
+	return 0, errors.New("ATM convention must be spot, atmf or dns")
+}
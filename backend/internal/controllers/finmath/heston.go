@@ -0,0 +1,233 @@
+package finmath
+
+import (
+	"backend/internal/controllers/dist"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+type HestonParams struct {
+	Kappa float64 `json:"kappa"` // Mean reversion speed
+	Theta float64 `json:"theta"` // Long-run variance
+	Xi    float64 `json:"xi"`    // Volatility of variance
+	Rho   float64 `json:"rho"`   // Spot/variance correlation
+	V0    float64 `json:"v0"`    // Initial variance
+}
+
+type HestonQuote struct {
+	Strike      float64 `json:"strike"`
+	Expiry      float64 `json:"expiry"`
+	ImpliedVol  float64 `json:"implied_vol"`
+	ModelVol    float64 `json:"model_vol"`
+	ModelPrice  float64 `json:"model_price"`
+	VolError    float64 `json:"vol_error"`
+	MarketPrice float64 `json:"market_price"`
+}
+
+type HestonCalibrationResult struct {
+	Params          HestonParams  `json:"params"`
+	RMSE            float64       `json:"rmse"`
+	FellerSatisfied bool          `json:"feller_satisfied"`
+	FellerRatio     float64       `json:"feller_ratio"`
+	Iterations      int           `json:"iterations"`
+	Converged       bool          `json:"converged"`
+	Quotes          []HestonQuote `json:"quotes"`
+}
+
+func (p HestonParams) Validate() error {
+	if p.Kappa <= 0 || p.Theta <= 0 || p.Xi <= 0 || p.V0 <= 0 {
+		return errors.New("kappa, theta, xi and v0 must be positive")
+	}
+	if p.Rho <= -1 || p.Rho >= 1 {
+		return errors.New("rho must be between -1 and 1")
+	}
+	return nil
+}
+
+// FellerRatio is 2κθ/ξ²; the variance process stays strictly positive when it is at least 1
+func (p HestonParams) FellerRatio() float64 {
+	return 2 * p.Kappa * p.Theta / (p.Xi * p.Xi)
+}
+
+// CharacteristicFunction returns E[exp(iu ln(S_T/S_0))] using the "little trap"
+// formulation of Albrecher et al., which avoids branch cuts of the complex log.
+func (p HestonParams) CharacteristicFunction(T, r, q float64) func(complex128) complex128 {
+	kappa := complex(p.Kappa, 0)
+	xi := complex(p.Xi, 0)
+	rho := complex(p.Rho, 0)
+	xi2 := xi * xi
+
+	return func(u complex128) complex128 {
+		iu := complex(0, 1) * u
+		beta := kappa - rho*xi*iu
+		d := cmplx.Sqrt(beta*beta + xi2*(iu+u*u))
+		g := (beta - d) / (beta + d)
+		edT := cmplx.Exp(-d * complex(T, 0))
+
+		C := complex((r-q)*T, 0)*iu +
+			complex(p.Kappa*p.Theta, 0)/xi2*((beta-d)*complex(T, 0)-2*cmplx.Log((1-g*edT)/(1-g)))
+		D := (beta - d) / xi2 * (1 - edT) / (1 - g*edT)
+
+		return cmplx.Exp(C + D*complex(p.V0, 0))
+	}
+}
+
+// First two cumulants of ln(S_T/S_0) under Heston (Fang and Oosterlee, 2008)
+func (p HestonParams) cumulants(T, r, q float64) (float64, float64) {
+	k, th, xi, rho, v0 := p.Kappa, p.Theta, p.Xi, p.Rho, p.V0
+	ekt := math.Exp(-k * T)
+
+	c1 := (r-q)*T + (1-ekt)*(th-v0)/(2*k) - th*T/2
+	c2 := (xi*T*k*ekt*(v0-th)*(8*k*rho-4*xi) +
+		k*rho*xi*(1-ekt)*(16*th-8*v0) +
+		2*th*k*T*(-4*k*rho*xi+xi*xi+4*k*k) +
+		xi*xi*((th-2*v0)*math.Exp(-2*k*T)+th*(6*ekt-7)+2*v0) +
+		8*k*k*(v0-th)*(1-ekt)) / (8 * k * k * k)
+
+	if c2 <= 0 || math.IsNaN(c2) {
+		// Fall back to the expected integrated variance
+		c2 = th*T + (v0-th)*(1-ekt)/k
+	}
+	return c1, c2
+}
+
+// HestonPrice prices a European option under the Heston stochastic volatility model
+func HestonPrice(p HestonParams, S, K, T, r, q float64, isCall bool) (float64, error) {
+	if S <= 0 || K <= 0 || T <= 0 {
+		return 0, errors.New("invalid parameters")
+	}
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+
+	c1, c2 := p.cumulants(T, r, q)
+	prices := cosSlice(p.CharacteristicFunction(T, r, q), c1, c2, S, []float64{K}, T, r, q, []bool{isCall})
+	return prices[0], nil
+}
+
+// CalibrateHeston fits (κ, θ, ξ, ρ, v0) to implied vols by least squares on
+// vega-weighted out-of-the-money prices, which approximates vol-space errors. Each
+// expiry is priced at its own zero rate.
+func CalibrateHeston(S float64, rates Rates, q float64, quotes []HestonQuote, initial *HestonParams) (HestonCalibrationResult, error) {
+	if S <= 0 {
+		return HestonCalibrationResult{}, errors.New("spot price must be positive")
+	}
+	if len(quotes) < 5 {
+		return HestonCalibrationResult{}, errors.New("at least 5 quotes are required to fit 5 parameters")
+	}
+
+	meanVar := 0.0
+	for _, qt := range quotes {
+		if qt.Strike <= 0 || qt.Expiry <= 0 || qt.ImpliedVol <= 0 {
+			return HestonCalibrationResult{}, errors.New("quote strikes, expiries and vols must be positive")
+		}
+		meanVar += qt.ImpliedVol * qt.ImpliedVol
+	}
+	meanVar /= float64(len(quotes))
+
+	// Group quotes by expiry so each slice shares one characteristic function evaluation
+	type slice struct {
+		T       float64
+		r       float64
+		index   []int
+		strikes []float64
+		isCall  []bool
+		market  []float64
+		vegas   []float64
+	}
+	byExpiry := map[float64]*slice{}
+	var expiries []float64
+	for i, qt := range quotes {
+		sl, ok := byExpiry[qt.Expiry]
+		if !ok {
+			sl = &slice{T: qt.Expiry, r: rates.ZeroRate(qt.Expiry)}
+			byExpiry[qt.Expiry] = sl
+			expiries = append(expiries, qt.Expiry)
+		}
+		r := sl.r
+		forward := S * math.Exp((r-q)*qt.Expiry)
+		call := qt.Strike >= forward
+		market := generalizedBlackScholes(call, S, qt.Strike, qt.Expiry, r, r-q, qt.ImpliedVol)
+		d1 := (math.Log(S/qt.Strike) + (r-q+qt.ImpliedVol*qt.ImpliedVol/2)*qt.Expiry) / (qt.ImpliedVol * math.Sqrt(qt.Expiry))
+		vega := S * math.Exp(-q*qt.Expiry) * math.Sqrt(qt.Expiry) * dist.NormalPDF(d1, 0, 1)
+
+		sl.index = append(sl.index, i)
+		sl.strikes = append(sl.strikes, qt.Strike)
+		sl.isCall = append(sl.isCall, call)
+		sl.market = append(sl.market, market)
+		sl.vegas = append(sl.vegas, math.Max(vega, 1e-4*S))
+	}
+	sort.Float64s(expiries)
+
+	toParams := func(x []float64) HestonParams {
+		return HestonParams{Kappa: x[0], Theta: x[1], Xi: x[2], Rho: x[3], V0: x[4]}
+	}
+
+	residuals := func(x []float64) []float64 {
+		p := toParams(x)
+		res := make([]float64, len(quotes))
+		for _, T := range expiries {
+			sl := byExpiry[T]
+			r := sl.r
+			c1, c2 := p.cumulants(T, r, q)
+			model := cosSlice(p.CharacteristicFunction(T, r, q), c1, c2, S, sl.strikes, T, r, q, sl.isCall)
+			for j, idx := range sl.index {
+				res[idx] = (model[j] - sl.market[j]) / sl.vegas[j]
+			}
+		}
+		return res
+	}
+
+	x0 := []float64{2, meanVar, 0.5, -0.5, meanVar}
+	if initial != nil {
+		if err := initial.Validate(); err != nil {
+			return HestonCalibrationResult{}, err
+		}
+		x0 = []float64{initial.Kappa, initial.Theta, initial.Xi, initial.Rho, initial.V0}
+	}
+	lower := []float64{1e-3, 1e-4, 1e-3, -0.999, 1e-4}
+	upper := []float64{20, 4, 5, 0.999, 4}
+
+	fit, err := opt.LevenbergMarquardt(residuals, x0, lower, upper, 1e-10, 200)
+	if err != nil {
+		return HestonCalibrationResult{}, err
+	}
+
+	params := toParams(fit.X)
+	result := HestonCalibrationResult{
+		Params:          params,
+		FellerRatio:     params.FellerRatio(),
+		FellerSatisfied: params.FellerRatio() >= 1,
+		Iterations:      fit.Iterations,
+		Converged:       fit.Converged,
+		Quotes:          make([]HestonQuote, len(quotes)),
+	}
+
+	sumSq := 0.0
+	for _, T := range expiries {
+		sl := byExpiry[T]
+		r := sl.r
+		c1, c2 := params.cumulants(T, r, q)
+		model := cosSlice(params.CharacteristicFunction(T, r, q), c1, c2, S, sl.strikes, T, r, q, sl.isCall)
+		for j, idx := range sl.index {
+			qt := quotes[idx]
+			qt.MarketPrice = sl.market[j]
+			qt.ModelPrice = model[j]
+			if vol, err := ImpliedVolatilityWithCarry(S, qt.Strike, T, r, q, model[j], sl.isCall[j]); err == nil {
+				qt.ModelVol = vol
+				qt.VolError = vol - qt.ImpliedVol
+			} else {
+				// Model price outside Black-Scholes bounds; report the vega-scaled error instead
+				qt.VolError = fit.Residuals[idx]
+			}
+			sumSq += qt.VolError * qt.VolError
+			result.Quotes[idx] = qt
+		}
+	}
+	result.RMSE = math.Sqrt(sumSq / float64(len(quotes)))
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package finmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHestonPrice(t *testing.T) {
+	// Fang and Oosterlee (2008), table 1: S = K = 100, T = 1, r = q = 0
+	p := HestonParams{Kappa: 1.5768, Theta: 0.0398, Xi: 0.5751, Rho: -0.5711, V0: 0.0175}
+	call, err := HestonPrice(p, 100, 100, 1, 0, 0, true)
+	if err != nil || math.Abs(call-5.785155450) > 1e-6 {
+		t.Errorf("call = %.9f (%v), want 5.785155450", call, err)
+	}
+
+	// Put-call parity holds across strikes with carry
+	for _, K := range []float64{70, 100, 140} {
+		call, _ := HestonPrice(p, 100, K, 0.5, 0.03, 0.01, true)
+		put, _ := HestonPrice(p, 100, K, 0.5, 0.03, 0.01, false)
+		want := 100*math.Exp(-0.01*0.5) - K*math.Exp(-0.03*0.5)
+		if math.Abs(call-put-want) > 1e-6 {
+			t.Errorf("K=%g: call-put = %.8f, want %.8f", K, call-put, want)
+		}
+	}
+}
+
+func TestCalibrateHestonRoundTrip(t *testing.T) {
+	S, r, q := 100.0, 0.03, 0.01
+	truth := HestonParams{Kappa: 2, Theta: 0.04, Xi: 0.5, Rho: -0.7, V0: 0.05}
+
+	var quotes []HestonQuote
+	for _, T := range []float64{0.25, 0.5, 1, 2} {
+		for _, K := range []float64{80, 90, 100, 110, 120} {
+			isCall := K >= S
+			price, err := HestonPrice(truth, S, K, T, r, q, isCall)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vol, err := ImpliedVolatilityWithCarry(S, K, T, r, q, price, isCall)
+			if err != nil {
+				t.Fatalf("K=%g T=%g: %v", K, T, err)
+			}
+			quotes = append(quotes, HestonQuote{Strike: K, Expiry: T, ImpliedVol: vol})
+		}
+	}
+
+	result, err := CalibrateHeston(S, FlatRate(r), q, quotes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RMSE > 1e-3 {
+		t.Errorf("rmse = %g, params %+v", result.RMSE, result.Params)
+	}
+	for _, qt := range result.Quotes {
+		if math.Abs(qt.VolError) > 2e-3 {
+			t.Errorf("K=%g T=%g: vol error %g", qt.Strike, qt.Expiry, qt.VolError)
+		}
+	}
+}
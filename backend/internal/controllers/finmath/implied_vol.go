@@ -0,0 +1,43 @@
+package finmath
+
+import (
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+// solveImpliedVol inverts a price function that is increasing in volatility.
+// It brackets the root on [1e-6, hi] and hands it to Brent.
+func solveImpliedVol(target, hi float64, price func(float64) float64) (float64, error) {
+	lo := 1e-6
+	if price(lo) > target {
+		return 0, errors.New("price is below intrinsic value")
+	}
+	for price(hi) < target {
+		hi *= 2
+		if hi > 1e6 {
+			return 0, errors.New("price is above the no-arbitrage upper bound")
+		}
+	}
+
+	return opt.BrentRoot(func(sigma float64) float64 {
+		return price(sigma) - target
+	}, lo, hi, 1e-10)
+}
+
+// ImpliedVolatilityWithCarry inverts the Black-Scholes formula with a continuous
+// dividend yield q. Unlike ImpliedVolatility it brackets the root, so it also
+// converges for deep in- or out-of-the-money quotes.
+func ImpliedVolatilityWithCarry(S, K, T, r, q, marketPrice float64, isCall bool) (float64, error) {
+	if S <= 0 || K <= 0 || T <= 0 || marketPrice <= 0 {
+		return 0, errors.New("invalid parameters")
+	}
+	if math.IsNaN(marketPrice) || math.IsInf(marketPrice, 0) {
+		return 0, errors.New("market price must be finite")
+	}
+
+	b := r - q
+	return solveImpliedVol(marketPrice, 1, func(sigma float64) float64 {
+		return generalizedBlackScholes(isCall, S, K, T, r, b, sigma)
+	})
+}
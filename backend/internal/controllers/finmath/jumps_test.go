@@ -0,0 +1,44 @@
+package finmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJumpModelsWithoutJumpsMatchBlackScholes(t *testing.T) {
+	S, T, r, q, v := 100.0, 0.75, 0.04, 0.01, 0.25
+	for _, K := range []float64{80, 100, 125} {
+		for _, isCall := range []bool{true, false} {
+			want := generalizedBlackScholes(isCall, S, K, T, r, r-q, v)
+
+			merton, err := MertonJumpDiffusion(isCall, S, K, T, r, q, v, MertonJumpParams{Lambda: 0, Mean: -0.1, Vol: 0.2})
+			if err != nil || math.Abs(merton-want) > 1e-10 {
+				t.Errorf("merton K=%g call=%v: got %v (%v), want %v", K, isCall, merton, err, want)
+			}
+			// Kou is priced by COS inversion, which truncates the density
+			kou, err := KouJumpDiffusion(isCall, S, K, T, r, q, v, KouJumpParams{Lambda: 0, P: 0.4, Eta1: 10, Eta2: 5})
+			if err != nil || math.Abs(kou-want) > 1e-4 {
+				t.Errorf("kou K=%g call=%v: got %v (%v), want %v", K, isCall, kou, err, want)
+			}
+		}
+	}
+}
+
+func TestMertonJumpDiffusionParity(t *testing.T) {
+	// Put-call parity must hold however many jumps are expected, including
+	// intensities whose Poisson weights underflow near zero
+	S, K, r, q, v := 100.0, 95.0, 0.05, 0.02, 0.2
+	for _, lambdaT := range []float64{0.5, 50, 2000} {
+		T := 1.0
+		jumps := MertonJumpParams{Lambda: lambdaT / T, Mean: 0, Vol: 0.01}
+		call, err1 := MertonJumpDiffusion(true, S, K, T, r, q, v, jumps)
+		put, err2 := MertonJumpDiffusion(false, S, K, T, r, q, v, jumps)
+		if err1 != nil || err2 != nil {
+			t.Fatalf("λT=%g: %v %v", lambdaT, err1, err2)
+		}
+		want := S*math.Exp(-q*T) - K*math.Exp(-r*T)
+		if call <= 0 || put <= 0 || math.Abs(call-put-want) > 1e-6 {
+			t.Errorf("λT=%g: call %.6f put %.6f, call-put want %.6f", lambdaT, call, put, want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package finmath
+
+import (
+	"backend/internal/controllers/sim"
+	"errors"
+	"math"
+	"sort"
+)
+
+// LocalVolSurface is a Dupire local volatility σ(S, t) sampled on a spot/time grid.
+// Vols holds one row per time.
+type LocalVolSurface struct {
+	Times  []float64   `json:"times"`
+	Spots  []float64   `json:"spots"`
+	Vols   [][]float64 `json:"vols"`
+	MinVol float64     `json:"min_vol"`
+	MaxVol float64     `json:"max_vol"`
+}
+
+type PathPayoff struct {
+	Type        string      `json:"type"` // european, barrier, asian or lookback
+	IsCall      bool        `json:"is_call"`
+	Strike      float64     `json:"strike"`
+	BarrierType BarrierType `json:"barrier_type"`
+	Barrier     float64     `json:"barrier"`
+	Rebate      float64     `json:"rebate"`
+}
+
+type LocalVolPrice struct {
+	Price     float64 `json:"price"`
+	StdError  float64 `json:"std_error"`
+	Paths     int     `json:"paths"`
+	Steps     int     `json:"steps"`
+	Seed      uint64  `json:"seed"`
+	Generator string  `json:"generator"`
+}
+
+// DupireLocalVol extracts local volatility from an implied vol surface with Gatheral's
+// total-variance form of Dupire's equation. Derivatives are taken numerically,
+// local vols are clipped to [minVol, maxVol] and then smoothed across spots
+// smoothing times.
+func DupireLocalVol(surface *VolSurface, spots, times []float64, minVol, maxVol float64, smoothing int) (*LocalVolSurface, error) {
+	if err := surface.Validate(); err != nil {
+		return nil, err
+	}
+	if len(spots) < 2 || len(times) < 2 {
+		return nil, errors.New("local vol grid needs at least 2 spots and 2 times")
+	}
+	if !sort.Float64sAreSorted(spots) || !sort.Float64sAreSorted(times) {
+		return nil, errors.New("grid spots and times must be increasing")
+	}
+	if spots[0] <= 0 || times[0] <= 0 {
+		return nil, errors.New("grid spots and times must be positive")
+	}
+	if minVol <= 0 {
+		minVol = 0.01
+	}
+	if maxVol <= minVol {
+		maxVol = 3
+	}
+
+	const hk = 1e-3
+	const ht = 1e-4
+
+	vols := make([][]float64, len(times))
+	for i, T := range times {
+		vols[i] = make([]float64, len(spots))
+		for j, K := range spots {
+			k := math.Log(K / surface.Forward(T))
+			w := surface.TotalVariance(k, T)
+			wUp := surface.TotalVariance(k+hk, T)
+			wDown := surface.TotalVariance(k-hk, T)
+			dwdk := (wUp - wDown) / (2 * hk)
+			d2wdk2 := (wUp - 2*w + wDown) / (hk * hk)
+
+			tDown := math.Max(T-ht, ht/2)
+			dwdt := (surface.TotalVariance(k, T+ht) - surface.TotalVariance(k, tDown)) / (T + ht - tDown)
+
+			denom := 1 - k/w*dwdk + 0.25*(-0.25-1/w+k*k/(w*w))*dwdk*dwdk + 0.5*d2wdk2
+
+			localVar := minVol * minVol
+			if w > 0 && denom > 1e-8 && dwdt > 0 {
+				localVar = dwdt / denom
+			}
+			vols[i][j] = math.Min(math.Max(math.Sqrt(localVar), minVol), maxVol)
+		}
+	}
+
+	for pass := 0; pass < smoothing; pass++ {
+		vols = boxSmooth(vols)
+	}
+
+	return &LocalVolSurface{Times: times, Spots: spots, Vols: vols, MinVol: minVol, MaxVol: maxVol}, nil
+}
+
+// boxSmooth applies a 3-point moving average across spots within each time row,
+// shrinking the window at the edges. Rows are left independent so the term
+// structure survives.
+func boxSmooth(grid [][]float64) [][]float64 {
+	out := make([][]float64, len(grid))
+	for i, row := range grid {
+		out[i] = make([]float64, len(row))
+		for j := range row {
+			lo, hi := max(j-1, 0), min(j+1, len(row)-1)
+			sum := 0.0
+			for _, v := range row[lo : hi+1] {
+				sum += v
+			}
+			out[i][j] = sum / float64(hi-lo+1)
+		}
+	}
+	return out
+}
+
+// bracket returns the lower grid index and interpolation weight for x, flat outside
+func bracket(grid []float64, x float64) (int, float64) {
+	n := len(grid)
+	if x <= grid[0] {
+		return 0, 0
+	}
+	if x >= grid[n-1] {
+		return n - 2, 1
+	}
+	i := sort.SearchFloat64s(grid, x) - 1
+	return i, (x - grid[i]) / (grid[i+1] - grid[i])
+}
+
+// Vol looks up σ(S, t) by bilinear interpolation
+func (lv *LocalVolSurface) Vol(S, t float64) float64 {
+	i, wt := bracket(lv.Times, t)
+	j, ws := bracket(lv.Spots, S)
+
+	v00, v01 := lv.Vols[i][j], lv.Vols[i][j+1]
+	v10, v11 := lv.Vols[i+1][j], lv.Vols[i+1][j+1]
+
+	return (1-wt)*((1-ws)*v00+ws*v01) + wt*((1-ws)*v10+ws*v11)
+}
+
+func (p PathPayoff) Validate() error {
+	switch p.Type {
+	case "european", "asian", "lookback":
+	case "barrier":
+		if p.Barrier <= 0 {
+			return errors.New("barrier must be positive")
+		}
+		switch p.BarrierType {
+		case DownAndIn, DownAndOut, UpAndIn, UpAndOut:
+		default:
+			return errors.New("unknown barrier type")
+		}
+	default:
+		return errors.New("payoff type must be european, barrier, asian or lookback")
+	}
+	if p.Type != "lookback" && p.Strike <= 0 {
+		return errors.New("strike must be positive")
+	}
+	return nil
+}
+
+// Evaluate returns the undiscounted payoff of a discretely monitored path.
+// Knock-out rebates are paid at expiry.
+func (p PathPayoff) Evaluate(path []float64) float64 {
+	last := path[len(path)-1]
+	vanilla := func(K, S float64) float64 {
+		if p.IsCall {
+			return math.Max(S-K, 0)
+		}
+		return math.Max(K-S, 0)
+	}
+
+	switch p.Type {
+	case "asian":
+		sum := 0.0
+		for _, s := range path[1:] {
+			sum += s
+		}
+		return vanilla(p.Strike, sum/float64(len(path)-1))
+
+	case "lookback":
+		// Floating strike: calls pay S_T - min, puts pay max - S_T
+		lo, hi := path[0], path[0]
+		for _, s := range path {
+			lo = math.Min(lo, s)
+			hi = math.Max(hi, s)
+		}
+		if p.IsCall {
+			return last - lo
+		}
+		return hi - last
+
+	case "barrier":
+		hit := false
+		for _, s := range path {
+			if (p.BarrierType == DownAndIn || p.BarrierType == DownAndOut) && s <= p.Barrier {
+				hit = true
+				break
+			}
+			if (p.BarrierType == UpAndIn || p.BarrierType == UpAndOut) && s >= p.Barrier {
+				hit = true
+				break
+			}
+		}
+		knockIn := p.BarrierType == DownAndIn || p.BarrierType == UpAndIn
+		if hit == knockIn {
+			return vanilla(p.Strike, last)
+		}
+		return p.Rebate
+	}
+
+	return vanilla(p.Strike, last)
+}
+
+// LocalVolMonteCarlo prices a path-dependent payoff by simulating the risk-neutral
+// spot under the local volatility surface, drifting along the forward curve
+func LocalVolMonteCarlo(lv *LocalVolSurface, payoff PathPayoff, S, T float64, rates Rates, q float64, steps, paths int, streams *sim.Streams) (LocalVolPrice, error) {
+	if err := payoff.Validate(); err != nil {
+		return LocalVolPrice{}, err
+	}
+	if paths < 2 {
+		return LocalVolPrice{}, errors.New("at least 2 paths are required")
+	}
+
+	simulated, err := sim.LocalVolatilityPaths(S, func(t0, t1 float64) float64 {
+		return (rates.ZeroRate(t1)*t1-rates.ZeroRate(t0)*t0)/(t1-t0) - q
+	}, T, steps, paths, lv.Vol, streams)
+	if err != nil {
+		return LocalVolPrice{}, err
+	}
+
+	sum, sumSq := 0.0, 0.0
+	for _, path := range simulated {
+		v := payoff.Evaluate(path)
+		sum += v
+		sumSq += v * v
+	}
+
+	n := float64(paths)
+	mean := sum / n
+	variance := math.Max((sumSq-n*mean*mean)/(n-1), 0)
+	discount := math.Exp(-rates.ZeroRate(T) * T)
+
+	return LocalVolPrice{
+		Price:     discount * mean,
+		StdError:  discount * math.Sqrt(variance/n),
+		Paths:     paths,
+		Steps:     steps,
+		Seed:      streams.Seed(),
+		Generator: streams.Generator(),
+	}, nil
+}
@@ -0,0 +1,289 @@
+package finmath
+
+import (
+	"backend/internal/controllers/dist"
+	"backend/internal/controllers/finmath/curve"
+	"errors"
+	"math"
+	"sort"
+)
+
+type RateVolModel string
+
+const (
+	Black     RateVolModel = "black"     // Lognormal forward, optionally shifted
+	Bachelier RateVolModel = "bachelier" // Normal forward; vols are in rate units
+)
+
+// RateVol is a flat vol (one value) or a term structure of vols by expiry,
+// interpolated linearly and held flat outside the nodes
+type RateVol struct {
+	Expiries []float64 `json:"expiries"`
+	Vols     []float64 `json:"vols"`
+}
+
+func (v RateVol) Validate() error {
+	if len(v.Vols) == 0 {
+		return errors.New("at least one vol is required")
+	}
+	if len(v.Vols) > 1 && len(v.Expiries) != len(v.Vols) {
+		return errors.New("expiries and vols must have the same length")
+	}
+	if !sort.Float64sAreSorted(v.Expiries) {
+		return errors.New("vol expiries must be increasing")
+	}
+	for _, s := range v.Vols {
+		if s <= 0 {
+			return errors.New("vols must be positive")
+		}
+	}
+	return nil
+}
+
+func (v RateVol) At(T float64) float64 {
+	n := len(v.Vols)
+	if n == 1 || T <= v.Expiries[0] {
+		return v.Vols[0]
+	}
+	if T >= v.Expiries[n-1] {
+		return v.Vols[n-1]
+	}
+	i := sort.SearchFloat64s(v.Expiries, T)
+	w := (T - v.Expiries[i-1]) / (v.Expiries[i] - v.Expiries[i-1])
+	return v.Vols[i-1] + w*(v.Vols[i]-v.Vols[i-1])
+}
+
+// RateOption is a call or put on a forward rate F fixing at T, undiscounted and
+// per unit of accrual. Shift displaces F and K under Black for negative rates.
+func RateOption(model RateVolModel, isCall bool, F, K, T, sigma, shift float64) (float64, error) {
+	switch model {
+	case Black:
+		if F+shift <= 0 || K+shift <= 0 {
+			return 0, errors.New("black needs positive shifted forwards and strikes")
+		}
+		return generalizedBlackScholes(isCall, F+shift, K+shift, T, 0, 0, sigma), nil
+	case Bachelier:
+		if T <= 0 {
+			if isCall {
+				return math.Max(F-K, 0), nil
+			}
+			return math.Max(K-F, 0), nil
+		}
+		sd := sigma * math.Sqrt(T)
+		d := (F - K) / sd
+		if isCall {
+			return (F-K)*dist.StandardNormalCDF(d) + sd*dist.NormalPDF(d, 0, 1), nil
+		}
+		return (K-F)*dist.StandardNormalCDF(-d) + sd*dist.NormalPDF(d, 0, 1), nil
+	}
+	return 0, errors.New("model must be black or bachelier")
+}
+
+// ImpliedRateVol inverts RateOption for an undiscounted price per unit of accrual
+func ImpliedRateVol(model RateVolModel, isCall bool, F, K, T, price, shift float64) (float64, error) {
+	if T <= 0 || price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, errors.New("expiry and price must be positive")
+	}
+	if _, err := RateOption(model, isCall, F, K, T, 1, shift); err != nil {
+		return 0, err
+	}
+	hi := 1.0
+	if model == Bachelier {
+		hi = 0.01
+	}
+	return solveImpliedVol(price, hi, func(sigma float64) float64 {
+		p, _ := RateOption(model, isCall, F, K, T, sigma, shift)
+		return p
+	})
+}
+
+type Caplet struct {
+	Start    float64 `json:"start"` // Fixing time
+	End      float64 `json:"end"`   // Payment time
+	Accrual  float64 `json:"accrual"`
+	Forward  float64 `json:"forward"`
+	Discount float64 `json:"discount_factor"`
+	Vol      float64 `json:"vol"`
+	Price    float64 `json:"price"`
+}
+
+type CapFloorResult struct {
+	Price      float64  `json:"price"`
+	ParRate    float64  `json:"par_rate"` // Swap rate over the caplet periods
+	Annuity    float64  `json:"annuity"`
+	ImpliedVol float64  `json:"implied_vol,omitempty"`
+	Caplets    []Caplet `json:"caplets"`
+}
+
+// CapFloor is a strip of caplets (or floorlets) on simple forwards over
+// [Start, Maturity] with accrual 1/Frequency, fixing in advance and paying in
+// arrears. A spot-starting cap omits the first caplet, whose rate is already fixed.
+type CapFloor struct {
+	IsCap     bool         `json:"is_cap"`
+	Start     float64      `json:"start"`
+	Maturity  float64      `json:"maturity"`
+	Frequency int          `json:"frequency"`
+	Strike    float64      `json:"strike"`
+	Notional  float64      `json:"notional"`
+	Model     RateVolModel `json:"model"`
+	Shift     float64      `json:"shift"`
+}
+
+func (cf CapFloor) periods() ([][2]float64, error) {
+	if cf.Frequency <= 0 || cf.Frequency > 12 || cf.Start < 0 || cf.Maturity <= cf.Start {
+		return nil, errors.New("cap needs 0 <= start < maturity and a frequency between 1 and 12")
+	}
+	tau := 1 / float64(cf.Frequency)
+	n := int(math.Round((cf.Maturity - cf.Start) / tau))
+	if n > 1200 {
+		return nil, errors.New("cap is limited to 1200 periods")
+	}
+	first := 0
+	if cf.Start == 0 {
+		first = 1
+	}
+	if n <= first {
+		return nil, errors.New("cap has no caplets left to price")
+	}
+	out := make([][2]float64, 0, n-first)
+	for i := first; i < n; i++ {
+		out = append(out, [2]float64{cf.Start + float64(i)*tau, cf.Start + float64(i+1)*tau})
+	}
+	return out, nil
+}
+
+// Price values the cap on a single curve, which both projects forwards and discounts
+func (cf CapFloor) Price(c curve.Curve, vol RateVol) (CapFloorResult, error) {
+	if err := vol.Validate(); err != nil {
+		return CapFloorResult{}, err
+	}
+	return cf.price(c, vol.At)
+}
+
+func (cf CapFloor) price(c curve.Curve, vol func(T float64) float64) (CapFloorResult, error) {
+	periods, err := cf.periods()
+	if err != nil {
+		return CapFloorResult{}, err
+	}
+	notional := cf.Notional
+	if notional == 0 {
+		notional = 1
+	}
+
+	result := CapFloorResult{Caplets: make([]Caplet, len(periods))}
+	for i, p := range periods {
+		tau := p[1] - p[0]
+		df := c.Discount(p[1])
+		fwd := (c.Discount(p[0])/df - 1) / tau
+		sigma := vol(p[0])
+		undiscounted, err := RateOption(cf.Model, cf.IsCap, fwd, cf.Strike, p[0], sigma, cf.Shift)
+		if err != nil {
+			return CapFloorResult{}, err
+		}
+		price := notional * tau * df * undiscounted
+		result.Caplets[i] = Caplet{Start: p[0], End: p[1], Accrual: tau, Forward: fwd, Discount: df, Vol: sigma, Price: price}
+		result.Price += price
+		result.Annuity += tau * df
+	}
+	result.ParRate = (c.Discount(periods[0][0]) - c.Discount(periods[len(periods)-1][1])) / result.Annuity
+	result.Annuity *= notional
+	return result, nil
+}
+
+// ImpliedVol is the flat vol that reprices the cap
+func (cf CapFloor) ImpliedVol(c curve.Curve, price float64) (CapFloorResult, error) {
+	if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return CapFloorResult{}, errors.New("price must be positive")
+	}
+	// Surface bad inputs before the search, which only sees prices
+	if _, err := cf.price(c, func(float64) float64 { return 0.01 }); err != nil {
+		return CapFloorResult{}, err
+	}
+	hi := 1.0
+	if cf.Model == Bachelier {
+		hi = 0.01
+	}
+	sigma, err := solveImpliedVol(price, hi, func(sigma float64) float64 {
+		r, _ := cf.price(c, func(float64) float64 { return sigma })
+		return r.Price
+	})
+	if err != nil {
+		return CapFloorResult{}, err
+	}
+	result, err := cf.price(c, func(float64) float64 { return sigma })
+	result.ImpliedVol = sigma
+	return result, err
+}
+
+type SwaptionResult struct {
+	Price       float64 `json:"price"`
+	ForwardRate float64 `json:"forward_swap_rate"`
+	Annuity     float64 `json:"annuity"`
+	Vol         float64 `json:"vol"`
+}
+
+// EuropeanSwaption is an option at Expiry to enter a swap over Tenor years with
+// fixed payments at Frequency. A payer swaption is a call on the swap rate.
+type EuropeanSwaption struct {
+	IsPayer   bool         `json:"is_payer"`
+	Expiry    float64      `json:"expiry"`
+	Tenor     float64      `json:"tenor"`
+	Frequency int          `json:"frequency"`
+	Strike    float64      `json:"strike"`
+	Notional  float64      `json:"notional"`
+	Model     RateVolModel `json:"model"`
+	Shift     float64      `json:"shift"`
+}
+
+// forward returns the forward swap rate and annuity per unit notional
+func (s EuropeanSwaption) forward(c curve.Curve) (float64, float64, error) {
+	if s.Expiry <= 0 || s.Tenor <= 0 || s.Frequency <= 0 || s.Frequency > 12 {
+		return 0, 0, errors.New("swaption needs a positive expiry and tenor and a frequency between 1 and 12")
+	}
+	tau := 1 / float64(s.Frequency)
+	n := int(math.Round(s.Tenor / tau))
+	if n < 1 || n > 1200 {
+		return 0, 0, errors.New("swap must have between 1 and 1200 fixed periods")
+	}
+	annuity := 0.0
+	for i := 1; i <= n; i++ {
+		annuity += tau * c.Discount(s.Expiry+float64(i)*tau)
+	}
+	return (c.Discount(s.Expiry) - c.Discount(s.Expiry+float64(n)*tau)) / annuity, annuity, nil
+}
+
+func (s EuropeanSwaption) notional() float64 {
+	if s.Notional == 0 {
+		return 1
+	}
+	return s.Notional
+}
+
+func (s EuropeanSwaption) Price(c curve.Curve, sigma float64) (SwaptionResult, error) {
+	if sigma <= 0 {
+		return SwaptionResult{}, errors.New("vol must be positive")
+	}
+	fwd, annuity, err := s.forward(c)
+	if err != nil {
+		return SwaptionResult{}, err
+	}
+	undiscounted, err := RateOption(s.Model, s.IsPayer, fwd, s.Strike, s.Expiry, sigma, s.Shift)
+	if err != nil {
+		return SwaptionResult{}, err
+	}
+	n := s.notional()
+	return SwaptionResult{Price: n * annuity * undiscounted, ForwardRate: fwd, Annuity: n * annuity, Vol: sigma}, nil
+}
+
+func (s EuropeanSwaption) ImpliedVol(c curve.Curve, price float64) (SwaptionResult, error) {
+	fwd, annuity, err := s.forward(c)
+	if err != nil {
+		return SwaptionResult{}, err
+	}
+	n := s.notional()
+	sigma, err := ImpliedRateVol(s.Model, s.IsPayer, fwd, s.Strike, s.Expiry, price/(n*annuity), s.Shift)
+	if err != nil {
+		return SwaptionResult{}, err
+	}
+	return SwaptionResult{Price: price, ForwardRate: fwd, Annuity: n * annuity, Vol: sigma}, nil
+}
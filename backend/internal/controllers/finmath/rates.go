@@ -0,0 +1,14 @@
+package finmath
+
+// Rates is a term structure of continuously compounded zero rates; stored
+// discount and parametric curves satisfy it
+type Rates interface {
+	ZeroRate(T float64) float64
+}
+
+// FlatRate is the same zero rate at every maturity
+type FlatRate float64
+
+func (r FlatRate) ZeroRate(float64) float64 {
+	return float64(r)
+}
@@ -0,0 +1,240 @@
+package finmath
+
+import (
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+type SABRParams struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+	Rho   float64 `json:"rho"`
+	Nu    float64 `json:"nu"`
+}
+
+type SABRExpansion string
+
+const (
+	SABRHagan  SABRExpansion = "hagan"  // Hagan et al. (2002) lognormal expansion
+	SABRObloj  SABRExpansion = "obloj"  // Obloj (2008) corrected lognormal expansion
+	SABRNormal SABRExpansion = "normal" // Hagan et al. (2002) normal (Bachelier) expansion
+)
+
+type SmilePoint struct {
+	Strike float64 `json:"strike"`
+	Vol    float64 `json:"vol"`
+}
+
+type SABRFitPoint struct {
+	Strike    float64 `json:"strike"`
+	MarketVol float64 `json:"market_vol"`
+	ModelVol  float64 `json:"model_vol"`
+	Error     float64 `json:"error"`
+}
+
+type SABRCalibrationResult struct {
+	Expiry     float64        `json:"expiry"`
+	Forward    float64        `json:"forward"`
+	Expansion  SABRExpansion  `json:"expansion"`
+	Params     SABRParams     `json:"params"`
+	RMSE       float64        `json:"rmse"`
+	Iterations int            `json:"iterations"`
+	Converged  bool           `json:"converged"`
+	Fit        []SABRFitPoint `json:"fit"`
+	Smile      []SmilePoint   `json:"smile"`
+}
+
+func (p SABRParams) Validate() error {
+	if p.Alpha <= 0 || p.Nu < 0 {
+		return errors.New("alpha must be positive and nu non-negative")
+	}
+	if p.Beta < 0 || p.Beta > 1 {
+		return errors.New("beta must be between 0 and 1")
+	}
+	if p.Rho <= -1 || p.Rho >= 1 {
+		return errors.New("rho must be between -1 and 1")
+	}
+	return nil
+}
+
+// zOverX returns z/x(z), which tends to 1 as z -> 0
+func zOverX(z, rho float64) float64 {
+	if math.Abs(z) < 1e-7 {
+		return 1 - rho*z/2
+	}
+	x := math.Log((math.Sqrt(1-2*rho*z+z*z) + z - rho) / (1 - rho))
+	return z / x
+}
+
+// SABRVol returns the implied volatility of strike K from the chosen SABR expansion.
+// The lognormal expansions give Black vols, the normal expansion a Bachelier vol.
+func SABRVol(expansion SABRExpansion, p SABRParams, F, K, T float64) (float64, error) {
+	if F <= 0 || K <= 0 || T <= 0 {
+		return 0, errors.New("forward, strike and expiry must be positive")
+	}
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+
+	alpha, beta, rho, nu := p.Alpha, p.Beta, p.Rho, p.Nu
+	omb := 1 - beta
+	logFK := math.Log(F / K)
+	fkBeta := math.Pow(F*K, omb/2)
+	denom := 1 + omb*omb/24*logFK*logFK + math.Pow(omb, 4)/1920*math.Pow(logFK, 4)
+	rhoTerm := rho * beta * nu * alpha / (4 * fkBeta)
+	nuTerm := (2 - 3*rho*rho) * nu * nu / 24
+
+	switch expansion {
+	case SABRHagan, "":
+		z := nu / alpha * fkBeta * logFK
+		correction := 1 + (omb*omb*alpha*alpha/(24*fkBeta*fkBeta)+rhoTerm+nuTerm)*T
+		return alpha / (fkBeta * denom) * zOverX(z, rho) * correction, nil
+
+	case SABRObloj:
+		var z float64
+		if math.Abs(omb) < 1e-12 {
+			z = nu * logFK / alpha
+		} else {
+			z = nu * (math.Pow(F, omb) - math.Pow(K, omb)) / (alpha * omb)
+		}
+		correction := 1 + (omb*omb*alpha*alpha/(24*fkBeta*fkBeta)+rhoTerm+nuTerm)*T
+
+		if math.Abs(logFK) < 1e-10 {
+			return alpha * math.Pow(F, -omb) * correction, nil
+		}
+		if nu == 0 {
+			// Deterministic CEV limit: ln(F/K) over the integral of dF/F^β,
+			// which is flat at α in the lognormal case
+			if math.Abs(omb) < 1e-12 {
+				return alpha * correction, nil
+			}
+			return alpha * logFK * omb / (math.Pow(F, omb) - math.Pow(K, omb)) * correction, nil
+		}
+		return nu * logFK / (z / zOverX(z, rho)) * correction, nil
+
+	case SABRNormal:
+		z := nu / alpha * fkBeta * logFK
+		numer := 1 + logFK*logFK/24 + math.Pow(logFK, 4)/1920
+		correction := 1 + (-beta*(2-beta)*alpha*alpha/(24*fkBeta*fkBeta)+rhoTerm+nuTerm)*T
+		return alpha * math.Pow(F*K, beta/2) * numer / denom * zOverX(z, rho) * correction, nil
+	}
+
+	return 0, errors.New("unknown SABR expansion")
+}
+
+// CalibrateSABR fits α, ρ and ν to one expiry's smile with β held fixed, as is
+// market practice. vols are Black vols for the lognormal expansions and normal
+// vols for SABRNormal.
+func CalibrateSABR(expansion SABRExpansion, F, T, beta float64, strikes, vols []float64) (SABRCalibrationResult, error) {
+	if F <= 0 || T <= 0 {
+		return SABRCalibrationResult{}, errors.New("forward and expiry must be positive")
+	}
+	if beta < 0 || beta > 1 {
+		return SABRCalibrationResult{}, errors.New("beta must be between 0 and 1")
+	}
+	if len(strikes) != len(vols) {
+		return SABRCalibrationResult{}, errors.New("strikes and vols must have the same length")
+	}
+	if len(strikes) < 3 {
+		return SABRCalibrationResult{}, errors.New("at least 3 quotes are required to fit alpha, rho and nu")
+	}
+	for i := range strikes {
+		if strikes[i] <= 0 || vols[i] <= 0 {
+			return SABRCalibrationResult{}, errors.New("strikes and vols must be positive")
+		}
+	}
+	if expansion == "" {
+		expansion = SABRHagan
+	}
+
+	// Seed alpha from the quote closest to the money
+	atm := 0
+	for i := range strikes {
+		if math.Abs(math.Log(strikes[i]/F)) < math.Abs(math.Log(strikes[atm]/F)) {
+			atm = i
+		}
+	}
+	alpha0 := vols[atm] * math.Pow(F, 1-beta)
+	if expansion == SABRNormal {
+		alpha0 = vols[atm] * math.Pow(F, -beta)
+	}
+
+	residuals := func(x []float64) []float64 {
+		p := SABRParams{Alpha: x[0], Beta: beta, Rho: x[1], Nu: x[2]}
+		res := make([]float64, len(strikes))
+		for i, K := range strikes {
+			model, err := SABRVol(expansion, p, F, K, T)
+			if err != nil || math.IsNaN(model) {
+				model = 0
+			}
+			res[i] = model - vols[i]
+		}
+		return res
+	}
+
+	fit, err := opt.LevenbergMarquardt(residuals,
+		[]float64{alpha0, -0.2, 0.5},
+		[]float64{1e-8, -0.9999, 1e-6},
+		[]float64{math.Inf(1), 0.9999, 10},
+		1e-12, 500)
+	if err != nil {
+		return SABRCalibrationResult{}, err
+	}
+
+	params := SABRParams{Alpha: fit.X[0], Beta: beta, Rho: fit.X[1], Nu: fit.X[2]}
+	result := SABRCalibrationResult{
+		Expiry:     T,
+		Forward:    F,
+		Expansion:  expansion,
+		Params:     params,
+		Iterations: fit.Iterations,
+		Converged:  fit.Converged,
+		Fit:        make([]SABRFitPoint, len(strikes)),
+	}
+
+	sumSq := 0.0
+	minK, maxK := strikes[0], strikes[0]
+	for i, K := range strikes {
+		e := fit.Residuals[i]
+		result.Fit[i] = SABRFitPoint{Strike: K, MarketVol: vols[i], ModelVol: vols[i] + e, Error: e}
+		sumSq += e * e
+		minK = math.Min(minK, K)
+		maxK = math.Max(maxK, K)
+	}
+	result.RMSE = math.Sqrt(sumSq / float64(len(strikes)))
+
+	smile, err := SABRSmile(expansion, params, F, T, minK, maxK, 50)
+	if err != nil {
+		return SABRCalibrationResult{}, err
+	}
+	result.Smile = smile
+
+	return result, nil
+}
+
+// SABRSmile samples the smile on an evenly spaced strike grid for plotting
+func SABRSmile(expansion SABRExpansion, p SABRParams, F, T, minK, maxK float64, points int) ([]SmilePoint, error) {
+	if minK <= 0 || maxK < minK {
+		return nil, errors.New("invalid strike range")
+	}
+	if points < 2 {
+		points = 2
+	}
+
+	smile := make([]SmilePoint, 0, points)
+	step := (maxK - minK) / float64(points-1)
+	for i := 0; i < points; i++ {
+		K := minK + float64(i)*step
+		vol, err := SABRVol(expansion, p, F, K, T)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(vol) || math.IsInf(vol, 0) {
+			continue
+		}
+		smile = append(smile, SmilePoint{Strike: K, Vol: vol})
+	}
+
+	return smile, nil
+}
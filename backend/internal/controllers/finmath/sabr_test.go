@@ -0,0 +1,41 @@
+package finmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalibrateSABRRoundTrip(t *testing.T) {
+	F, T := 0.03, 2.0
+	strikes := []float64{0.015, 0.02, 0.025, 0.03, 0.035, 0.04, 0.05}
+	tests := []struct {
+		expansion SABRExpansion
+		params    SABRParams
+	}{
+		{SABRHagan, SABRParams{Alpha: 0.035, Beta: 0.5, Rho: -0.3, Nu: 0.4}},
+		{SABRObloj, SABRParams{Alpha: 0.2, Beta: 1, Rho: 0.2, Nu: 0.6}},
+		{SABRNormal, SABRParams{Alpha: 0.008, Beta: 0, Rho: -0.1, Nu: 0.3}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.expansion), func(t *testing.T) {
+			vols := make([]float64, len(strikes))
+			for i, K := range strikes {
+				v, err := SABRVol(tt.expansion, tt.params, F, K, T)
+				if err != nil {
+					t.Fatal(err)
+				}
+				vols[i] = v
+			}
+
+			result, err := CalibrateSABR(tt.expansion, F, T, tt.params.Beta, strikes, vols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := result.Params
+			if math.Abs(got.Alpha-tt.params.Alpha) > 1e-3*tt.params.Alpha ||
+				math.Abs(got.Rho-tt.params.Rho) > 1e-3 || math.Abs(got.Nu-tt.params.Nu) > 1e-3 {
+				t.Errorf("got %+v, want %+v (rmse %g)", got, tt.params, result.RMSE)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package shortrate
+
+import (
+	"backend/internal/controllers/finmath/curve"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+// Quote is a market cap or swaption price per unit notional. Maturity is the
+// cap maturity or the swaption expiry.
+type Quote struct {
+	Instrument string  `json:"instrument"` // "cap" or "swaption"
+	Maturity   float64 `json:"maturity"`
+	Tenor      float64 `json:"tenor,omitempty"`
+	Strike     float64 `json:"strike"`
+	Frequency  int     `json:"frequency"`
+	IsPayer    bool    `json:"is_payer,omitempty"`
+	Price      float64 `json:"price"`
+	ModelPrice float64 `json:"model_price"`
+	Error      float64 `json:"error"`
+}
+
+type CalibrationResult struct {
+	Model      ModelType `json:"model"`
+	Params     Params    `json:"params"`
+	RMSE       float64   `json:"rmse"`
+	Iterations int       `json:"iterations"`
+	Converged  bool      `json:"converged"`
+	Quotes     []Quote   `json:"quotes"`
+}
+
+func (q Quote) price(m Model) (float64, error) {
+	switch q.Instrument {
+	case "cap":
+		return Cap(m, q.Maturity, q.Strike, q.Frequency)
+	case "swaption":
+		return Swaption(m, q.IsPayer, q.Maturity, q.Tenor, q.Strike, q.Frequency)
+	}
+	return 0, errors.New("quote instrument must be cap or swaption")
+}
+
+// Calibrate fits the model to cap and swaption prices by least squares on relative
+// price errors. Hull-White fits (a, σ) on top of the initial curve; Vasicek and CIR
+// fit all four parameters. initial seeds the search when given.
+func Calibrate(model ModelType, quotes []Quote, initialCurve curve.Curve, initial *Params) (CalibrationResult, error) {
+	for _, q := range quotes {
+		if q.Price <= 0 || q.Maturity <= 0 || q.Frequency <= 0 {
+			return CalibrationResult{}, errors.New("quote prices, maturities and frequencies must be positive")
+		}
+		if q.Instrument == "swaption" && q.Tenor <= 0 {
+			return CalibrationResult{}, errors.New("swaption quotes need a positive tenor")
+		}
+	}
+
+	var x0, lower, upper []float64
+	var toParams func(x []float64) Params
+	switch model {
+	case HullWhiteModel:
+		if initialCurve == nil {
+			return CalibrationResult{}, errors.New("hull-white calibration needs an initial curve")
+		}
+		x0 = []float64{0.1, 0.01}
+		lower = []float64{1e-4, 1e-5}
+		upper = []float64{5, 1}
+		toParams = func(x []float64) Params { return Params{Kappa: x[0], Sigma: x[1]} }
+	case VasicekModel, CIRModel:
+		x0 = []float64{0.3, 0.04, 0.02, 0.03}
+		if model == CIRModel {
+			x0[2] = 0.1
+		}
+		lower = []float64{1e-3, 1e-4, 1e-4, 0}
+		upper = []float64{5, 0.5, 2, 0.5}
+		toParams = func(x []float64) Params { return Params{Kappa: x[0], Theta: x[1], Sigma: x[2], R0: x[3]} }
+	default:
+		return CalibrationResult{}, errors.New("model must be vasicek, cir or hull_white")
+	}
+	if len(quotes) < len(x0) {
+		return CalibrationResult{}, errors.New("need at least as many quotes as model parameters")
+	}
+	if initial != nil {
+		seed := []float64{initial.Kappa, initial.Sigma}
+		if model != HullWhiteModel {
+			seed = []float64{initial.Kappa, initial.Theta, initial.Sigma, initial.R0}
+		}
+		for i := range seed {
+			x0[i] = math.Min(math.Max(seed[i], lower[i]), upper[i])
+		}
+	}
+
+	prices := func(x []float64) ([]float64, error) {
+		m, err := New(model, toParams(x), initialCurve)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]float64, len(quotes))
+		for i, q := range quotes {
+			if out[i], err = q.price(m); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	// Validate the quotes once so that LM only sees numerical failures
+	if _, err := prices(x0); err != nil {
+		return CalibrationResult{}, err
+	}
+
+	residuals := func(x []float64) []float64 {
+		res := make([]float64, len(quotes))
+		fitted, err := prices(x)
+		for i, q := range quotes {
+			if err != nil {
+				res[i] = 1e3
+				continue
+			}
+			res[i] = fitted[i]/q.Price - 1
+		}
+		return res
+	}
+
+	fit, err := opt.LevenbergMarquardt(residuals, x0, lower, upper, 1e-12, 300)
+	if err != nil {
+		return CalibrationResult{}, err
+	}
+
+	params := toParams(fit.X)
+	fitted, err := prices(fit.X)
+	if err != nil {
+		return CalibrationResult{}, err
+	}
+
+	result := CalibrationResult{
+		Model:      model,
+		Params:     params,
+		Iterations: fit.Iterations,
+		Converged:  fit.Converged,
+		Quotes:     make([]Quote, len(quotes)),
+	}
+	sumSq := 0.0
+	for i, q := range quotes {
+		q.ModelPrice = fitted[i]
+		q.Error = fitted[i] - q.Price
+		result.Quotes[i] = q
+		sumSq += fit.Residuals[i] * fit.Residuals[i]
+	}
+	result.RMSE = math.Sqrt(sumSq / float64(len(quotes)))
+	return result, nil
+}
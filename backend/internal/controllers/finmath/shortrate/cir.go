@@ -0,0 +1,60 @@
+package shortrate
+
+import (
+	"backend/internal/controllers/dist"
+	"math"
+)
+
+// CIR is dr = κ(θ - r)dt + σ√r dW
+type CIR struct {
+	Params
+}
+
+func (c CIR) gamma() float64 {
+	return math.Sqrt(c.Kappa*c.Kappa + 2*c.Sigma*c.Sigma)
+}
+
+// ab returns ln A(τ) and B(τ) with P = A e^{-B r}
+func (c CIR) ab(tau float64) (float64, float64) {
+	g := c.gamma()
+	e := math.Expm1(g * tau)
+	denom := (g+c.Kappa)*e + 2*g
+	b := 2 * e / denom
+	lnA := 2 * c.Kappa * c.Theta / (c.Sigma * c.Sigma) * (math.Log(2*g) + (c.Kappa+g)*tau/2 - math.Log(denom))
+	return lnA, b
+}
+
+func (c CIR) BondPrice(t, T, r float64) float64 {
+	lnA, b := c.ab(T - t)
+	return math.Exp(lnA - b*r)
+}
+
+func (c CIR) Discount(T float64) float64 {
+	return c.BondPrice(0, T, c.R0)
+}
+
+// BondOption uses Cox, Ingersoll and Ross's (1985) noncentral chi-square formula
+func (c CIR) BondOption(isCall bool, T, S, K float64) float64 {
+	pT, pS := c.Discount(T), c.Discount(S)
+
+	g := c.gamma()
+	s2 := c.Sigma * c.Sigma
+	lnA, b := c.ab(S - T)
+	rho := 2 * g / (s2 * math.Expm1(g*T))
+	psi := (c.Kappa + g) / s2
+	rStar := (lnA - math.Log(K)) / b
+	df := 4 * c.Kappa * c.Theta / s2
+
+	call := 0.0
+	if rStar > 0 {
+		nc1 := 2 * rho * rho * c.R0 * math.Exp(g*T) / (rho + psi + b)
+		nc2 := 2 * rho * rho * c.R0 * math.Exp(g*T) / (rho + psi)
+		call = pS*dist.NoncentralChiSquareCDF(2*rStar*(rho+psi+b), df, nc1) -
+			K*pT*dist.NoncentralChiSquareCDF(2*rStar*(rho+psi), df, nc2)
+	}
+
+	if isCall {
+		return math.Max(call, 0)
+	}
+	return math.Max(call-pS+K*pT, 0)
+}
@@ -0,0 +1,43 @@
+package shortrate
+
+import (
+	"backend/internal/controllers/finmath/curve"
+	"math"
+)
+
+// HullWhite is dr = (θ(t) - a r)dt + σ dW with θ fitted to the initial curve
+type HullWhite struct {
+	A     float64
+	Sigma float64
+	Curve curve.Curve
+}
+
+func (hw HullWhite) b(tau float64) float64 {
+	return (1 - math.Exp(-hw.A*tau)) / hw.A
+}
+
+func (hw HullWhite) Discount(T float64) float64 {
+	return hw.Curve.Discount(T)
+}
+
+// BondPrice reprices the initial curve exactly when r is the expected short rate
+func (hw HullWhite) BondPrice(t, T, r float64) float64 {
+	if t <= 0 {
+		return hw.Curve.Discount(T)
+	}
+	b := hw.b(T - t)
+	f := hw.Curve.ForwardRate(t, t)
+	lnA := math.Log(hw.Curve.Discount(T)/hw.Curve.Discount(t)) + b*f -
+		hw.Sigma*hw.Sigma/(4*hw.A)*(1-math.Exp(-2*hw.A*t))*b*b
+	return math.Exp(lnA - b*r)
+}
+
+func (hw HullWhite) BondOption(isCall bool, T, S, K float64) float64 {
+	return gaussianBondOption(isCall, hw.A, hw.Sigma, hw.Discount(T), hw.Discount(S), T, S, K)
+}
+
+// Shift is φ(t) with r(t) = x(t) + φ(t), where x is a zero-mean OU process
+func (hw HullWhite) Shift(t float64) float64 {
+	e := 1 - math.Exp(-hw.A*t)
+	return hw.Curve.ForwardRate(t, t) + hw.Sigma*hw.Sigma/(2*hw.A*hw.A)*e*e
+}
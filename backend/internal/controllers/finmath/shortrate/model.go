@@ -0,0 +1,122 @@
+package shortrate
+
+import (
+	"backend/internal/controllers/finmath/curve"
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+// Model is a one-factor short-rate model with closed-form zero-coupon bonds.
+// Times are in years from today.
+type Model interface {
+	// Discount is today's price of a zero-coupon bond maturing at T
+	Discount(T float64) float64
+	// BondPrice is the price at t of a zero maturing at T when the short rate is r
+	BondPrice(t, T, r float64) float64
+	// BondOption prices a European option expiring at T on a zero maturing at S
+	BondOption(isCall bool, T, S, K float64) float64
+}
+
+// Params holds the model inputs. Hull-White uses Kappa and Sigma only and takes
+// its drift from the initial curve.
+type Params struct {
+	Kappa float64 `json:"kappa"` // Mean reversion speed
+	Theta float64 `json:"theta"` // Long-run mean rate
+	Sigma float64 `json:"sigma"`
+	R0    float64 `json:"r0"`
+}
+
+type ModelType string
+
+const (
+	VasicekModel   ModelType = "vasicek"
+	CIRModel       ModelType = "cir"
+	HullWhiteModel ModelType = "hull_white"
+)
+
+// New builds a model; initial is required for Hull-White and ignored otherwise
+func New(model ModelType, p Params, initial curve.Curve) (Model, error) {
+	if p.Kappa <= 0 || p.Sigma <= 0 {
+		return nil, errors.New("kappa and sigma must be positive")
+	}
+	switch model {
+	case VasicekModel:
+		return Vasicek{p}, nil
+	case CIRModel:
+		if p.Theta <= 0 || p.R0 < 0 {
+			return nil, errors.New("CIR needs a positive theta and non-negative r0")
+		}
+		return CIR{p}, nil
+	case HullWhiteModel:
+		if initial == nil {
+			return nil, errors.New("hull-white needs an initial curve")
+		}
+		return HullWhite{A: p.Kappa, Sigma: p.Sigma, Curve: initial}, nil
+	}
+	return nil, errors.New("model must be vasicek, cir or hull_white")
+}
+
+// Cap prices a cap per unit notional as a strip of caplets, each a put on a zero
+// bond. The first period, already fixed, is excluded.
+func Cap(m Model, maturity, strike float64, frequency int) (float64, error) {
+	if frequency <= 0 || maturity*float64(frequency) < 2-1e-9 {
+		return 0, errors.New("cap needs a positive frequency and at least two periods")
+	}
+	tau := 1 / float64(frequency)
+	periods := int(math.Round(maturity * float64(frequency)))
+
+	price := 0.0
+	for i := 1; i < periods; i++ {
+		start, end := float64(i)*tau, float64(i+1)*tau
+		price += (1 + strike*tau) * m.BondOption(false, start, end, 1/(1+strike*tau))
+	}
+	return price, nil
+}
+
+// Swaption prices a European swaption per unit notional by Jamshidian's
+// decomposition of the coupon bond option into zero-bond options. A payer
+// swaption is a put on the fixed-leg coupon bond struck at par.
+func Swaption(m Model, isPayer bool, expiry, tenor, strike float64, frequency int) (float64, error) {
+	if expiry <= 0 || tenor <= 0 || frequency <= 0 {
+		return 0, errors.New("expiry, tenor and frequency must be positive")
+	}
+	tau := 1 / float64(frequency)
+	n := int(math.Round(tenor * float64(frequency)))
+	if n < 1 {
+		return 0, errors.New("tenor must cover at least one fixed period")
+	}
+
+	times := make([]float64, n)
+	coupons := make([]float64, n)
+	for i := range times {
+		times[i] = expiry + float64(i+1)*tau
+		coupons[i] = strike * tau
+	}
+	coupons[n-1] += 1
+
+	couponBond := func(r float64) float64 {
+		v := 0.0
+		for i, t := range times {
+			v += coupons[i] * m.BondPrice(expiry, t, r)
+		}
+		return v - 1
+	}
+	lo, hi := -0.5, 0.5
+	for couponBond(lo)*couponBond(hi) > 0 {
+		lo, hi = lo*2, hi*2
+		if hi > 50 {
+			return 0, errors.New("failed to find the critical rate")
+		}
+	}
+	rStar, err := opt.BrentRoot(couponBond, lo, hi, 1e-14)
+	if err != nil {
+		return 0, err
+	}
+
+	price := 0.0
+	for i, t := range times {
+		price += coupons[i] * m.BondOption(!isPayer, expiry, t, m.BondPrice(expiry, t, rStar))
+	}
+	return price, nil
+}
@@ -0,0 +1,167 @@
+package shortrate
+
+import (
+	"errors"
+	"math"
+)
+
+// Tree is a Hull-White (1994) trinomial tree for the short rate fitted to the
+// initial curve. Node (i, j) carries the dt-period rate Alpha[i] + j·Dx.
+type Tree struct {
+	Dt    float64
+	Dx    float64
+	JMax  int
+	Alpha []float64
+	// Branch probabilities (down, middle, up) and the middle target of node j
+	probs [][3]float64
+	mid   []int
+}
+
+func (t *Tree) width() int { return 2*t.JMax + 1 }
+
+// NewTree builds steps time steps of length dt and fits α so that the tree
+// reprices every zero bond on the step grid
+func NewTree(hw HullWhite, dt float64, steps int) (*Tree, error) {
+	if dt <= 0 || steps <= 0 {
+		return nil, errors.New("tree needs a positive step and step count")
+	}
+	if steps > 5000 {
+		return nil, errors.New("tree is limited to 5000 steps")
+	}
+
+	// Edge nodes switch branching at the first j above 0.184/(aΔt), which keeps
+	// every probability positive. No step i reaches beyond node i, so a tree
+	// with fewer steps than that never branches from an edge and stops at steps.
+	a := hw.A
+	jmax := math.Min(math.Ceil(0.184/(a*dt)), float64(steps))
+	if math.IsNaN(jmax) || jmax > 2000 {
+		return nil, errors.New("mean reversion is too weak for this tree; increase kappa, the step length or lower the step count")
+	}
+
+	m := math.Expm1(-a * dt)
+	t := &Tree{
+		Dt:    dt,
+		Dx:    hw.Sigma * math.Sqrt(3*dt),
+		JMax:  int(jmax),
+		Alpha: make([]float64, steps),
+	}
+
+	w := t.width()
+	t.probs = make([][3]float64, w)
+	t.mid = make([]int, w)
+	for j := -t.JMax; j <= t.JMax; j++ {
+		jm := float64(j) * m
+		k := j + t.JMax
+		switch {
+		case j == t.JMax:
+			// Branch down: targets j, j-1, j-2
+			t.mid[k] = j - 1
+			t.probs[k] = [3]float64{1.0/6 + (jm*jm+jm)/2, -1.0/3 - jm*jm - 2*jm, 7.0/6 + (jm*jm+3*jm)/2}
+		case j == -t.JMax:
+			// Branch up: targets j, j+1, j+2
+			t.mid[k] = j + 1
+			t.probs[k] = [3]float64{7.0/6 + (jm*jm-3*jm)/2, -1.0/3 - jm*jm + 2*jm, 1.0/6 + (jm*jm-jm)/2}
+		default:
+			t.mid[k] = j
+			t.probs[k] = [3]float64{1.0/6 + (jm*jm-jm)/2, 2.0/3 - jm*jm, 1.0/6 + (jm*jm+jm)/2}
+		}
+	}
+
+	// Forward induction of Arrow-Debreu prices fixes α step by step
+	q := make([]float64, w)
+	q[t.JMax] = 1
+	for i := 0; i < steps; i++ {
+		reach := t.reach(i)
+		sum := 0.0
+		for j := -reach; j <= reach; j++ {
+			sum += q[j+t.JMax] * math.Exp(-float64(j)*t.Dx*dt)
+		}
+		t.Alpha[i] = (math.Log(sum) - math.Log(hw.Discount(float64(i+1)*dt))) / dt
+
+		next := make([]float64, w)
+		for j := -reach; j <= reach; j++ {
+			k := j + t.JMax
+			value := q[k] * math.Exp(-(t.Alpha[i]+float64(j)*t.Dx)*dt)
+			for b := 0; b < 3; b++ {
+				next[t.mid[k]-1+b+t.JMax] += value * t.probs[k][b]
+			}
+		}
+		q = next
+	}
+	return t, nil
+}
+
+// reach is the widest node index live at step i
+func (t *Tree) reach(i int) int {
+	if i < t.JMax {
+		return i
+	}
+	return t.JMax
+}
+
+// rollback discounts values at step i+1 back to step i
+func (t *Tree) rollback(i int, values []float64) []float64 {
+	out := make([]float64, t.width())
+	reach := t.reach(i)
+	for j := -reach; j <= reach; j++ {
+		k := j + t.JMax
+		expected := 0.0
+		for b := 0; b < 3; b++ {
+			expected += t.probs[k][b] * values[t.mid[k]-1+b+t.JMax]
+		}
+		out[k] = expected * math.Exp(-(t.Alpha[i]+float64(j)*t.Dx)*t.Dt)
+	}
+	return out
+}
+
+// TreeBondOption prices a European or American option expiring at T on a zero
+// maturing at S. The grid has steps nodes to expiry and the bond maturity is
+// rounded to the nearest node.
+func TreeBondOption(hw HullWhite, isCall, american bool, T, S, K float64, steps int) (float64, error) {
+	if T <= 0 || S <= T {
+		return 0, errors.New("bond maturity must be after a positive option expiry")
+	}
+	if steps <= 0 {
+		steps = 100
+	}
+	dt := T / float64(steps)
+	total := int(math.Round(S / dt))
+	if total <= steps {
+		total = steps + 1
+	}
+
+	tree, err := NewTree(hw, dt, total)
+	if err != nil {
+		return 0, err
+	}
+
+	bond := make([]float64, tree.width())
+	for j := range bond {
+		bond[j] = 1
+	}
+	for i := total - 1; i >= steps; i-- {
+		bond = tree.rollback(i, bond)
+	}
+
+	payoff := func(b float64) float64 {
+		if isCall {
+			return math.Max(b-K, 0)
+		}
+		return math.Max(K-b, 0)
+	}
+
+	option := make([]float64, tree.width())
+	for k := range option {
+		option[k] = payoff(bond[k])
+	}
+	for i := steps - 1; i >= 0; i-- {
+		option = tree.rollback(i, option)
+		if american {
+			bond = tree.rollback(i, bond)
+			for k := range option {
+				option[k] = math.Max(option[k], payoff(bond[k]))
+			}
+		}
+	}
+	return option[tree.JMax], nil
+}
@@ -0,0 +1,50 @@
+package shortrate
+
+import (
+	"backend/internal/controllers/dist"
+	"math"
+)
+
+// Vasicek is dr = κ(θ - r)dt + σ dW
+type Vasicek struct {
+	Params
+}
+
+func (v Vasicek) b(tau float64) float64 {
+	return (1 - math.Exp(-v.Kappa*tau)) / v.Kappa
+}
+
+func (v Vasicek) BondPrice(t, T, r float64) float64 {
+	tau := T - t
+	k, s := v.Kappa, v.Sigma
+	b := v.b(tau)
+	lnA := (v.Theta-s*s/(2*k*k))*(b-tau) - s*s*b*b/(4*k)
+	return math.Exp(lnA - b*r)
+}
+
+func (v Vasicek) Discount(T float64) float64 {
+	return v.BondPrice(0, T, v.R0)
+}
+
+func (v Vasicek) BondOption(isCall bool, T, S, K float64) float64 {
+	return gaussianBondOption(isCall, v.Kappa, v.Sigma, v.Discount(T), v.Discount(S), T, S, K)
+}
+
+// gaussianBondOption is the zero-bond option formula shared by Vasicek and Hull-White,
+// where the forward bond price is lognormal
+func gaussianBondOption(isCall bool, a, sigma, pT, pS, T, S, K float64) float64 {
+	sigmaP := sigma / a * (1 - math.Exp(-a*(S-T))) * math.Sqrt((1-math.Exp(-2*a*T))/(2*a))
+	if sigmaP < 1e-14 {
+		if isCall {
+			return math.Max(pS-K*pT, 0)
+		}
+		return math.Max(K*pT-pS, 0)
+	}
+
+	h := math.Log(pS/(pT*K))/sigmaP + sigmaP/2
+	N := dist.StandardNormalCDF
+	if isCall {
+		return pS*N(h) - K*pT*N(h-sigmaP)
+	}
+	return K*pT*N(-h+sigmaP) - pS*N(-h)
+}
@@ -0,0 +1,229 @@
+package finmath
+
+import (
+	"backend/internal/controllers/opt"
+	"errors"
+	"math"
+)
+
+type LegType string
+
+const (
+	CallLeg       LegType = "call"
+	PutLeg        LegType = "put"
+	UnderlyingLeg LegType = "underlying"
+)
+
+// StrategyLeg is one position. Premium is the entry price per unit and defaults to
+// today's Black-Scholes value; Volatility overrides the strategy's vol for this leg.
+type StrategyLeg struct {
+	Type       LegType  `json:"type"`
+	Strike     float64  `json:"strike"`
+	Expiry     float64  `json:"expiry"`
+	Quantity   float64  `json:"quantity"`
+	Short      bool     `json:"short"`
+	Premium    *float64 `json:"premium"`
+	Volatility float64  `json:"volatility"`
+}
+
+type Strategy struct {
+	Spot  float64       `json:"spot_price"`
+	R     float64       `json:"risk_free_rate"`
+	Sigma float64       `json:"volatility"`
+	Legs  []StrategyLeg `json:"legs"`
+
+	// Rates, when set, replaces R with a zero curve
+	Rates Rates `json:"-"`
+}
+
+type PayoffPoint struct {
+	Spot      float64 `json:"spot"`
+	PnLExpiry float64 `json:"pnl_expiry"`
+	PnLToday  float64 `json:"pnl_today"`
+}
+
+type StrategyResult struct {
+	NetPremium float64            `json:"net_premium"` // Paid when positive
+	Horizon    float64            `json:"horizon"`     // First expiry, where pnl_expiry is measured
+	Payoff     []PayoffPoint      `json:"payoff"`
+	Breakevens []float64          `json:"breakevens"`
+	MaxProfit  *float64           `json:"max_profit"` // Nil when unbounded
+	MaxLoss    *float64           `json:"max_loss"`   // Nil when unbounded, otherwise negative
+	Greeks     map[string]float64 `json:"greeks"`
+}
+
+func (l StrategyLeg) sign() float64 {
+	if l.Short {
+		return -l.Quantity
+	}
+	return l.Quantity
+}
+
+func (s Strategy) vol(l StrategyLeg) float64 {
+	if l.Volatility > 0 {
+		return l.Volatility
+	}
+	return s.Sigma
+}
+
+// rate is the continuously compounded rate from elapsed to T
+func (s Strategy) rate(elapsed, T float64) float64 {
+	if s.Rates == nil {
+		return s.R
+	}
+	return forwardRate(s.Rates.ZeroRate(elapsed), elapsed, s.Rates.ZeroRate(T), T)
+}
+
+// value is the leg's unit value at spot S once elapsed years have passed
+func (s Strategy) value(l StrategyLeg, S, elapsed float64) float64 {
+	if l.Type == UnderlyingLeg {
+		return S
+	}
+	T := l.Expiry - elapsed
+	if T <= 1e-12 {
+		if l.Type == CallLeg {
+			return math.Max(S-l.Strike, 0)
+		}
+		return math.Max(l.Strike-S, 0)
+	}
+	call, put := BlackScholes(math.Max(S, 1e-12), l.Strike, T, s.rate(elapsed, l.Expiry), s.vol(l))
+	if l.Type == CallLeg {
+		return call
+	}
+	return put
+}
+
+func (s Strategy) validate() error {
+	if s.Spot <= 0 {
+		return errors.New("spot price must be positive")
+	}
+	if len(s.Legs) == 0 || len(s.Legs) > 20 {
+		return errors.New("a strategy needs between 1 and 20 legs")
+	}
+	for _, l := range s.Legs {
+		if l.Quantity <= 0 {
+			return errors.New("leg quantities must be positive")
+		}
+		switch l.Type {
+		case UnderlyingLeg:
+		case CallLeg, PutLeg:
+			if l.Strike <= 0 || l.Expiry <= 0 {
+				return errors.New("option legs need a positive strike and expiry")
+			}
+			if s.vol(l) <= 0 {
+				return errors.New("option legs need a positive volatility")
+			}
+		default:
+			return errors.New("leg type must be call, put or underlying")
+		}
+	}
+	return nil
+}
+
+// Analyze evaluates the strategy on points spots spanning [minSpot, maxSpot]. P&L
+// at expiry is measured at the first option expiry, with later legs still priced
+// by Black-Scholes, so calendars show their usual tent.
+func (s Strategy) Analyze(minSpot, maxSpot float64, points int) (StrategyResult, error) {
+	if err := s.validate(); err != nil {
+		return StrategyResult{}, err
+	}
+	if minSpot < 0 || maxSpot <= minSpot || points < 2 {
+		return StrategyResult{}, errors.New("spot grid needs 0 <= min < max and at least 2 points")
+	}
+
+	premiums := make([]float64, len(s.Legs))
+	horizon := math.Inf(1)
+	result := StrategyResult{Breakevens: []float64{}, Greeks: map[string]float64{"delta": 0, "gamma": 0, "theta": 0, "vega": 0, "rho": 0}}
+	for i, l := range s.Legs {
+		if l.Premium != nil {
+			premiums[i] = *l.Premium
+		} else {
+			premiums[i] = s.value(l, s.Spot, 0)
+		}
+		result.NetPremium += l.sign() * premiums[i]
+		if l.Type != UnderlyingLeg {
+			horizon = math.Min(horizon, l.Expiry)
+		}
+	}
+	if math.IsInf(horizon, 1) {
+		horizon = 0
+	}
+	result.Horizon = horizon
+
+	pnl := func(S, elapsed float64) float64 {
+		total := 0.0
+		for i, l := range s.Legs {
+			total += l.sign() * (s.value(l, S, elapsed) - premiums[i])
+		}
+		return total
+	}
+	atExpiry := func(S float64) float64 { return pnl(S, horizon) }
+
+	step := (maxSpot - minSpot) / float64(points-1)
+	result.Payoff = make([]PayoffPoint, points)
+	for i := range result.Payoff {
+		S := minSpot + float64(i)*step
+		result.Payoff[i] = PayoffPoint{Spot: S, PnLExpiry: atExpiry(S), PnLToday: pnl(S, 0)}
+	}
+
+	// Breakevens from sign changes on the grid, refined with Brent
+	for i := 1; i < points; i++ {
+		a, b := result.Payoff[i-1], result.Payoff[i]
+		switch {
+		case a.PnLExpiry == 0:
+			result.Breakevens = append(result.Breakevens, a.Spot)
+		case (a.PnLExpiry < 0) != (b.PnLExpiry < 0) && b.PnLExpiry != 0:
+			if root, err := opt.BrentRoot(atExpiry, a.Spot, b.Spot, 1e-10); err == nil {
+				result.Breakevens = append(result.Breakevens, root)
+			}
+		}
+	}
+	if last := result.Payoff[points-1]; last.PnLExpiry == 0 {
+		result.Breakevens = append(result.Breakevens, last.Spot)
+	}
+
+	// Extremes over the grid, the strikes and zero. As S grows the payoff moves with
+	// the net position in calls and the underlying; puts go worthless.
+	candidates := []float64{0}
+	slope := 0.0
+	for _, l := range s.Legs {
+		if l.Type != UnderlyingLeg {
+			candidates = append(candidates, l.Strike)
+		}
+		if l.Type != PutLeg {
+			slope += l.sign()
+		}
+	}
+	for _, p := range result.Payoff {
+		candidates = append(candidates, p.Spot)
+	}
+	maxP, minP := math.Inf(-1), math.Inf(1)
+	for _, S := range candidates {
+		v := atExpiry(S)
+		maxP, minP = math.Max(maxP, v), math.Min(minP, v)
+	}
+	if slope <= 1e-12 {
+		result.MaxProfit = &maxP
+	}
+	if slope >= -1e-12 {
+		result.MaxLoss = &minP
+	}
+
+	for _, l := range s.Legs {
+		if l.Type == UnderlyingLeg {
+			result.Greeks["delta"] += l.sign()
+			continue
+		}
+		g := Greeks(s.Spot, l.Strike, l.Expiry, s.rate(0, l.Expiry), s.vol(l))
+		prefix := "call_"
+		if l.Type == PutLeg {
+			prefix = "put_"
+		}
+		result.Greeks["delta"] += l.sign() * g[prefix+"delta"]
+		result.Greeks["gamma"] += l.sign() * g["gamma"]
+		result.Greeks["theta"] += l.sign() * g[prefix+"theta"]
+		result.Greeks["vega"] += l.sign() * g["vega"]
+		result.Greeks["rho"] += l.sign() * g[prefix+"rho"]
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package swaps
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/finmath/curve"
+	"errors"
+	"time"
+)
+
+// FRA is a forward rate agreement settled at Start on the discounted difference
+// between the fixing and Rate. Pay marks the buyer, who pays the fixed rate.
+type FRA struct {
+	Valuation  time.Time
+	Start      time.Time
+	End        time.Time
+	Notional   float64
+	Rate       float64
+	Pay        bool
+	DayCount   calendar.DayCount
+	Calendar   *calendar.Calendar
+	Convention calendar.BusinessDayConvention
+	Discount   curve.Curve
+	Projection curve.Curve
+}
+
+type FRAResult struct {
+	NPV              float64 `json:"npv"`
+	ParRate          float64 `json:"par_rate"` // Forward rate implied by the projection curve
+	PV01             float64 `json:"pv01"`
+	AccrualFraction  float64 `json:"accrual_fraction"`
+	SettlementAmount float64 `json:"settlement_amount"` // Paid at the start date
+	StartDate        string  `json:"start_date"`
+	EndDate          string  `json:"end_date"`
+}
+
+func (f FRA) Price() (FRAResult, error) {
+	if f.Discount == nil || f.Projection == nil {
+		return FRAResult{}, errors.New("discount and projection curves are required")
+	}
+	if f.Notional <= 0 {
+		return FRAResult{}, errors.New("notional must be positive")
+	}
+	if _, err := calendar.ParseDayCount(string(f.DayCount)); err != nil {
+		return FRAResult{}, err
+	}
+
+	start, err := f.Calendar.Adjust(f.Start, f.Convention)
+	if err != nil {
+		return FRAResult{}, err
+	}
+	end, err := f.Calendar.Adjust(f.End, f.Convention)
+	if err != nil {
+		return FRAResult{}, err
+	}
+	if !end.After(start) {
+		return FRAResult{}, errors.New("FRA end must be after its start")
+	}
+	if start.Before(calendar.Date(f.Valuation)) {
+		return FRAResult{}, errors.New("FRA has already fixed")
+	}
+
+	years := func(t time.Time) float64 {
+		return calendar.Act365Fixed.YearFraction(f.Valuation, t, nil)
+	}
+	tau := f.DayCount.YearFraction(start, end, f.Calendar)
+	forward := (f.Projection.Discount(years(start))/f.Projection.Discount(years(end)) - 1) / tau
+
+	sign := 1.0
+	if !f.Pay {
+		sign = -1
+	}
+	// Settlement at the start is the end-date payment discounted at the fixing
+	settlement := sign * f.Notional * tau * (forward - f.Rate) / (1 + tau*forward)
+	df := f.Discount.Discount(years(start))
+
+	return FRAResult{
+		NPV:              settlement * df,
+		ParRate:          forward,
+		PV01:             -sign * f.Notional * tau / (1 + tau*forward) * df * 1e-4,
+		AccrualFraction:  tau,
+		SettlementAmount: settlement,
+		StartDate:        start.Format(dateLayout),
+		EndDate:          end.Format(dateLayout),
+	}, nil
+}
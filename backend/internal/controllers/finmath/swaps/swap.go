@@ -0,0 +1,206 @@
+package swaps
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/finmath/curve"
+	"errors"
+	"math"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type LegType string
+
+const (
+	Fixed LegType = "fixed"
+	Float LegType = "float"
+)
+
+// Leg is one side of a swap. Float legs project coupons off Projection and add
+// Spread; Fixing, when set, is used for a period that has already started.
+type Leg struct {
+	Type       LegType
+	Pay        bool
+	Rate       float64
+	Spread     float64
+	Frequency  int
+	DayCount   calendar.DayCount
+	Fixing     *float64
+	Projection curve.Curve
+}
+
+// Swap exchanges two legs over [Start, End] on a common notional. Curve times are
+// ACT/365F years from Valuation.
+type Swap struct {
+	Valuation  time.Time
+	Start      time.Time
+	End        time.Time
+	Notional   float64
+	Legs       []Leg
+	Calendar   *calendar.Calendar
+	Convention calendar.BusinessDayConvention
+	Stub       calendar.StubType
+	Discount   curve.Curve
+}
+
+type Cashflow struct {
+	StartDate       string  `json:"start_date"`
+	EndDate         string  `json:"end_date"`
+	PaymentDate     string  `json:"payment_date"`
+	AccrualFraction float64 `json:"accrual_fraction"`
+	Rate            float64 `json:"rate"` // Fixed rate, or projected forward plus spread
+	Amount          float64 `json:"amount"`
+	DiscountFactor  float64 `json:"discount_factor"`
+	PresentValue    float64 `json:"present_value"`
+}
+
+type LegResult struct {
+	Type      LegType    `json:"type"`
+	Pay       bool       `json:"pay"`
+	PV        float64    `json:"pv"`
+	PV01      float64    `json:"pv01"` // PV change for +1bp on the leg's rate or spread
+	Cashflows []Cashflow `json:"cashflows"`
+}
+
+type SwapResult struct {
+	NPV       float64     `json:"npv"`
+	ParRate   float64     `json:"par_rate"` // Fixed rate, or spread on the quoted float leg, that zeroes the NPV
+	QuotedLeg int         `json:"quoted_leg"`
+	PV01      float64     `json:"pv01"`
+	Legs      []LegResult `json:"legs"`
+}
+
+func (s Swap) years(t time.Time) float64 {
+	return calendar.Act365Fixed.YearFraction(s.Valuation, t, nil)
+}
+
+func (l Leg) validate() error {
+	switch l.Type {
+	case Fixed:
+	case Float:
+		if l.Projection == nil {
+			return errors.New("float legs need a projection curve")
+		}
+	default:
+		return errors.New("leg type must be fixed or float")
+	}
+	if _, err := calendar.ParseDayCount(string(l.DayCount)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// priceLeg values a leg and its annuity, the PV of one unit of rate or spread
+func (s Swap) priceLeg(l Leg) (LegResult, float64, error) {
+	periods, err := calendar.GenerateSchedule(s.Start, s.End, l.Frequency, s.Stub, s.Convention, s.Calendar, true)
+	if err != nil {
+		return LegResult{}, 0, err
+	}
+
+	sign := 1.0
+	if l.Pay {
+		sign = -1
+	}
+
+	result := LegResult{Type: l.Type, Pay: l.Pay, Cashflows: []Cashflow{}}
+	annuity := 0.0
+	for _, p := range periods {
+		if !p.AdjustedEnd.After(s.Valuation) {
+			continue
+		}
+
+		tau := l.DayCount.YearFraction(p.AdjustedStart, p.AdjustedEnd, s.Calendar)
+
+		rate := l.Rate
+		if l.Type == Float {
+			if !p.AdjustedStart.After(s.Valuation) && l.Fixing != nil {
+				rate = *l.Fixing
+			} else {
+				t1 := math.Max(s.years(p.AdjustedStart), 0)
+				t2 := s.years(p.AdjustedEnd)
+				rate = (l.Projection.Discount(t1)/l.Projection.Discount(t2) - 1) / tau
+			}
+			rate += l.Spread
+		}
+
+		df := s.Discount.Discount(s.years(p.AdjustedEnd))
+		amount := sign * s.Notional * tau * rate
+		result.PV += amount * df
+		annuity += s.Notional * tau * df
+
+		result.Cashflows = append(result.Cashflows, Cashflow{
+			StartDate:       p.AdjustedStart.Format(dateLayout),
+			EndDate:         p.AdjustedEnd.Format(dateLayout),
+			PaymentDate:     p.AdjustedEnd.Format(dateLayout),
+			AccrualFraction: tau,
+			Rate:            rate,
+			Amount:          amount,
+			DiscountFactor:  df,
+			PresentValue:    amount * df,
+		})
+	}
+
+	result.PV01 = sign * annuity * 1e-4
+	return result, annuity, nil
+}
+
+// Price values each leg off the discount curve. The par rate is quoted on the fixed
+// leg when there is one, otherwise as a spread on the first leg.
+func (s Swap) Price() (SwapResult, error) {
+	if len(s.Legs) != 2 {
+		return SwapResult{}, errors.New("a swap needs exactly two legs")
+	}
+	if s.Discount == nil {
+		return SwapResult{}, errors.New("a discount curve is required")
+	}
+	if s.Notional <= 0 {
+		return SwapResult{}, errors.New("notional must be positive")
+	}
+	if !s.End.After(s.Valuation) {
+		return SwapResult{}, errors.New("swap has already matured")
+	}
+
+	quoted := 0
+	for i, l := range s.Legs {
+		if err := l.validate(); err != nil {
+			return SwapResult{}, err
+		}
+		if l.Type == Fixed {
+			quoted = i
+		}
+	}
+	if s.Legs[0].Type == Fixed && s.Legs[1].Type == Fixed {
+		return SwapResult{}, errors.New("at least one leg must float")
+	}
+
+	result := SwapResult{QuotedLeg: quoted}
+	var quotedAnnuity float64
+	for i, l := range s.Legs {
+		leg, annuity, err := s.priceLeg(l)
+		if err != nil {
+			return SwapResult{}, err
+		}
+		result.NPV += leg.PV
+		result.Legs = append(result.Legs, leg)
+		if i == quoted {
+			quotedAnnuity = annuity
+		}
+	}
+
+	q := s.Legs[quoted]
+	current := q.Rate
+	if q.Type == Float {
+		current = q.Spread
+	}
+	sign := 1.0
+	if q.Pay {
+		sign = -1
+	}
+	if quotedAnnuity > 0 {
+		result.ParRate = current - result.NPV/(sign*quotedAnnuity)
+	}
+	result.PV01 = result.Legs[quoted].PV01
+
+	return result, nil
+}
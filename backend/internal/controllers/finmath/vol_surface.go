@@ -0,0 +1,499 @@
+package finmath
+
+import (
+	"backend/internal/controllers/opt"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type SurfaceModel string
+
+const (
+	SurfaceSVI  SurfaceModel = "svi"
+	SurfaceSSVI SurfaceModel = "ssvi"
+)
+
+// SVIParams is Gatheral's raw SVI slice w(k) = a + b(ρ(k-m) + sqrt((k-m)² + σ²))
+type SVIParams struct {
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Rho   float64 `json:"rho"`
+	M     float64 `json:"m"`
+	Sigma float64 `json:"sigma"`
+}
+
+// SSVIParams is the Gatheral-Jacquier surface SVI with power-law φ(θ) = η/(θ^γ(1+θ)^(1-γ))
+type SSVIParams struct {
+	Rho   float64 `json:"rho"`
+	Eta   float64 `json:"eta"`
+	Gamma float64 `json:"gamma"`
+}
+
+type SurfaceSlice struct {
+	Expiry  float64    `json:"expiry"`
+	Forward float64    `json:"forward"`
+	SVI     *SVIParams `json:"svi,omitempty"`
+	Theta   float64    `json:"theta,omitempty"` // ATM total variance, SSVI only
+}
+
+// VolSurface is a fitted implied volatility surface; it round-trips through JSON
+// so clients can send a previously fitted surface back for querying. Slice
+// forwards carry the rate curve it was fitted on, so Rate is only a fallback.
+type VolSurface struct {
+	Model    SurfaceModel   `json:"model"`
+	Spot     float64        `json:"spot_price"`
+	Rate     float64        `json:"risk_free_rate"`
+	Dividend float64        `json:"dividend_yield"`
+	SSVI     *SSVIParams    `json:"ssvi,omitempty"`
+	Slices   []SurfaceSlice `json:"slices"`
+
+	rates Rates
+}
+
+type ChainQuote struct {
+	Strike     float64 `json:"strike"`
+	Expiry     float64 `json:"expiry"`
+	Price      float64 `json:"price"`
+	IsCall     bool    `json:"is_call"`
+	ImpliedVol float64 `json:"implied_vol"`
+}
+
+type SurfaceQuoteFit struct {
+	Strike    float64 `json:"strike"`
+	Expiry    float64 `json:"expiry"`
+	MarketVol float64 `json:"market_vol"`
+	ModelVol  float64 `json:"model_vol"`
+	Error     float64 `json:"error"`
+}
+
+type RejectedQuote struct {
+	Strike float64 `json:"strike"`
+	Expiry float64 `json:"expiry"`
+	Reason string  `json:"reason"`
+}
+
+type ArbitrageViolation struct {
+	Type         string  `json:"type"`
+	Expiry       float64 `json:"expiry"`
+	NextExpiry   float64 `json:"next_expiry,omitempty"`
+	LogMoneyness float64 `json:"log_moneyness"`
+	Value        float64 `json:"value"`
+}
+
+type ArbitrageReport struct {
+	ButterflyFree bool                 `json:"butterfly_free"`
+	CalendarFree  bool                 `json:"calendar_free"`
+	Violations    []ArbitrageViolation `json:"violations"`
+}
+
+type VolSurfaceFit struct {
+	Surface   VolSurface        `json:"surface"`
+	RMSE      float64           `json:"rmse"`
+	Quotes    []SurfaceQuoteFit `json:"quotes"`
+	Rejected  []RejectedQuote   `json:"rejected"`
+	Arbitrage ArbitrageReport   `json:"arbitrage"`
+}
+
+func (p SVIParams) TotalVariance(k float64) float64 {
+	d := k - p.M
+	return p.A + p.B*(p.Rho*d+math.Sqrt(d*d+p.Sigma*p.Sigma))
+}
+
+func (p SSVIParams) phi(theta float64) float64 {
+	return p.Eta / (math.Pow(theta, p.Gamma) * math.Pow(1+theta, 1-p.Gamma))
+}
+
+func (p SSVIParams) TotalVariance(k, theta float64) float64 {
+	pk := p.phi(theta) * k
+	return theta / 2 * (1 + p.Rho*pk + math.Sqrt((pk+p.Rho)*(pk+p.Rho)+1-p.Rho*p.Rho))
+}
+
+func (s *VolSurface) Validate() error {
+	if s.Spot <= 0 {
+		return errors.New("surface spot price must be positive")
+	}
+	if len(s.Slices) == 0 {
+		return errors.New("surface has no slices")
+	}
+	if s.Model != SurfaceSVI && s.Model != SurfaceSSVI {
+		return errors.New("surface model must be svi or ssvi")
+	}
+	if s.Model == SurfaceSSVI && s.SSVI == nil {
+		return errors.New("ssvi surface is missing its parameters")
+	}
+	for i, sl := range s.Slices {
+		if sl.Expiry <= 0 {
+			return errors.New("slice expiries must be positive")
+		}
+		if i > 0 && sl.Expiry <= s.Slices[i-1].Expiry {
+			return errors.New("slices must be sorted by increasing expiry")
+		}
+		if s.Model == SurfaceSVI && sl.SVI == nil {
+			return fmt.Errorf("slice %g is missing svi parameters", sl.Expiry)
+		}
+		if s.Model == SurfaceSSVI && sl.Theta <= 0 {
+			return fmt.Errorf("slice %g needs a positive atm total variance", sl.Expiry)
+		}
+	}
+	return nil
+}
+
+// SetRates prices forwards off a term structure instead of the slice forwards
+func (s *VolSurface) SetRates(rates Rates) {
+	s.rates = rates
+}
+
+// Forward uses the surface's rates when set. Otherwise ln F(T) is interpolated
+// linearly in T between slice forwards and its carry extended flat beyond them,
+// falling back to Rate when slices have no forwards.
+func (s *VolSurface) Forward(T float64) float64 {
+	if s.rates != nil {
+		return s.Spot * math.Exp((s.rates.ZeroRate(T)-s.Dividend)*T)
+	}
+	n := len(s.Slices)
+	for _, sl := range s.Slices {
+		if sl.Forward <= 0 {
+			return s.Spot * math.Exp((s.Rate-s.Dividend)*T)
+		}
+	}
+	carry := func(i int) float64 { return math.Log(s.Slices[i].Forward / s.Spot) }
+	switch {
+	case n == 0:
+		return s.Spot * math.Exp((s.Rate-s.Dividend)*T)
+	case T <= s.Slices[0].Expiry:
+		return s.Spot * math.Exp(carry(0)*T/s.Slices[0].Expiry)
+	case T >= s.Slices[n-1].Expiry:
+		return s.Spot * math.Exp(carry(n-1)*T/s.Slices[n-1].Expiry)
+	}
+	i := sort.Search(n, func(j int) bool { return s.Slices[j].Expiry >= T }) - 1
+	t0, t1 := s.Slices[i].Expiry, s.Slices[i+1].Expiry
+	return s.Spot * math.Exp(carry(i)+(carry(i+1)-carry(i))*(T-t0)/(t1-t0))
+}
+
+// ZeroRate is the rate implied by Forward, so the surface can drive simulations
+func (s *VolSurface) ZeroRate(T float64) float64 {
+	if s.rates != nil {
+		return s.rates.ZeroRate(T)
+	}
+	T = math.Max(T, 1e-8)
+	return math.Log(s.Forward(T)/s.Spot)/T + s.Dividend
+}
+
+func (s *VolSurface) sliceVariance(i int, k float64) float64 {
+	if s.Model == SurfaceSSVI {
+		return s.SSVI.TotalVariance(k, s.Slices[i].Theta)
+	}
+	return s.Slices[i].SVI.TotalVariance(k)
+}
+
+// TotalVariance returns w(k, T) at log-moneyness k = ln(K/F(T)). Between slices
+// total variance is interpolated linearly in T; outside them it scales with T.
+func (s *VolSurface) TotalVariance(k, T float64) float64 {
+	n := len(s.Slices)
+	if T <= s.Slices[0].Expiry {
+		return s.sliceVariance(0, k) * T / s.Slices[0].Expiry
+	}
+	if T >= s.Slices[n-1].Expiry {
+		return s.sliceVariance(n-1, k) * T / s.Slices[n-1].Expiry
+	}
+
+	i := sort.Search(n, func(j int) bool { return s.Slices[j].Expiry >= T }) - 1
+	t0, t1 := s.Slices[i].Expiry, s.Slices[i+1].Expiry
+
+	if s.Model == SurfaceSSVI {
+		theta := s.Slices[i].Theta + (s.Slices[i+1].Theta-s.Slices[i].Theta)*(T-t0)/(t1-t0)
+		return s.SSVI.TotalVariance(k, theta)
+	}
+
+	w0, w1 := s.sliceVariance(i, k), s.sliceVariance(i+1, k)
+	return w0 + (w1-w0)*(T-t0)/(t1-t0)
+}
+
+// ImpliedVol returns σ(K, T) from the fitted surface
+func (s *VolSurface) ImpliedVol(K, T float64) (float64, error) {
+	if K <= 0 || T <= 0 {
+		return 0, errors.New("strike and expiry must be positive")
+	}
+	w := s.TotalVariance(math.Log(K/s.Forward(T)), T)
+	if w <= 0 || math.IsNaN(w) {
+		return 0, fmt.Errorf("surface has non-positive variance at strike %g, expiry %g", K, T)
+	}
+	return math.Sqrt(w / T), nil
+}
+
+// Grid evaluates σ(K, T) on the cartesian product of strikes and expiries,
+// one row per expiry
+func (s *VolSurface) Grid(strikes, expiries []float64) ([][]float64, error) {
+	grid := make([][]float64, len(expiries))
+	for i, T := range expiries {
+		grid[i] = make([]float64, len(strikes))
+		for j, K := range strikes {
+			vol, err := s.ImpliedVol(K, T)
+			if err != nil {
+				return nil, err
+			}
+			grid[i][j] = vol
+		}
+	}
+	return grid, nil
+}
+
+// CheckArbitrage tests Gatheral's butterfly density condition g(k) >= 0 on each slice
+// and that total variance is non-decreasing across slices, on a log-moneyness grid.
+func (s *VolSurface) CheckArbitrage(kMin, kMax float64) ArbitrageReport {
+	report := ArbitrageReport{ButterflyFree: true, CalendarFree: true, Violations: []ArbitrageViolation{}}
+	const points = 41
+	const h = 1e-4
+
+	for i, sl := range s.Slices {
+		for j := 0; j < points; j++ {
+			k := kMin + (kMax-kMin)*float64(j)/(points-1)
+			w := s.sliceVariance(i, k)
+			wp := (s.sliceVariance(i, k+h) - s.sliceVariance(i, k-h)) / (2 * h)
+			wpp := (s.sliceVariance(i, k+h) - 2*w + s.sliceVariance(i, k-h)) / (h * h)
+
+			var g float64
+			if w <= 0 {
+				g = -1
+			} else {
+				g = (1-k*wp/(2*w))*(1-k*wp/(2*w)) - wp*wp/4*(1/w+0.25) + wpp/2
+			}
+			if g < -1e-8 {
+				report.ButterflyFree = false
+				report.Violations = append(report.Violations, ArbitrageViolation{
+					Type: "butterfly", Expiry: sl.Expiry, LogMoneyness: k, Value: g,
+				})
+			}
+
+			if i+1 < len(s.Slices) {
+				diff := s.sliceVariance(i+1, k) - w
+				if diff < -1e-10 {
+					report.CalendarFree = false
+					report.Violations = append(report.Violations, ArbitrageViolation{
+						Type: "calendar", Expiry: sl.Expiry, NextExpiry: s.Slices[i+1].Expiry, LogMoneyness: k, Value: diff,
+					})
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+type surfacePoint struct {
+	quote ChainQuote
+	k     float64
+	vol   float64
+}
+
+// BuildVolSurface inverts an option chain to implied vols, fits SVI per expiry or
+// SSVI across expiries, and checks the fit for static arbitrage. Each expiry is
+// discounted at its own zero rate.
+func BuildVolSurface(S float64, rates Rates, q float64, model SurfaceModel, quotes []ChainQuote) (VolSurfaceFit, error) {
+	if S <= 0 {
+		return VolSurfaceFit{}, errors.New("spot price must be positive")
+	}
+	if model == "" {
+		model = SurfaceSVI
+	}
+	if model != SurfaceSVI && model != SurfaceSSVI {
+		return VolSurfaceFit{}, errors.New("model must be svi or ssvi")
+	}
+
+	surface := VolSurface{Model: model, Spot: S, Dividend: q, rates: rates}
+	fit := VolSurfaceFit{Rejected: []RejectedQuote{}}
+
+	byExpiry := map[float64][]surfacePoint{}
+	for _, qt := range quotes {
+		if qt.Strike <= 0 || qt.Expiry <= 0 {
+			fit.Rejected = append(fit.Rejected, RejectedQuote{qt.Strike, qt.Expiry, "strike and expiry must be positive"})
+			continue
+		}
+
+		vol := qt.ImpliedVol
+		if vol <= 0 {
+			if qt.Price <= 0 {
+				fit.Rejected = append(fit.Rejected, RejectedQuote{qt.Strike, qt.Expiry, "quote needs a price or an implied vol"})
+				continue
+			}
+			// A continuous dividend is equivalent to discounting the spot
+			r := rates.ZeroRate(qt.Expiry)
+			var err error
+			vol, err = ImpliedVolatility(S*math.Exp(-q*qt.Expiry), qt.Strike, qt.Expiry, r, qt.Price, qt.IsCall)
+			if err != nil || vol <= 0 {
+				vol, err = ImpliedVolatilityWithCarry(S, qt.Strike, qt.Expiry, r, q, qt.Price, qt.IsCall)
+			}
+			if err != nil {
+				fit.Rejected = append(fit.Rejected, RejectedQuote{qt.Strike, qt.Expiry, err.Error()})
+				continue
+			}
+		}
+
+		k := math.Log(qt.Strike / surface.Forward(qt.Expiry))
+		byExpiry[qt.Expiry] = append(byExpiry[qt.Expiry], surfacePoint{quote: qt, k: k, vol: vol})
+	}
+
+	var expiries []float64
+	for T := range byExpiry {
+		expiries = append(expiries, T)
+	}
+	sort.Float64s(expiries)
+	if len(expiries) == 0 {
+		return VolSurfaceFit{}, errors.New("no usable quotes in the option chain")
+	}
+
+	var err error
+	if model == SurfaceSVI {
+		err = fitSVISlices(&surface, expiries, byExpiry)
+	} else {
+		err = fitSSVI(&surface, expiries, byExpiry)
+	}
+	if err != nil {
+		return VolSurfaceFit{}, err
+	}
+	surface.Rate = rates.ZeroRate(expiries[len(expiries)-1])
+
+	kMin, kMax := 0.0, 0.0
+	sumSq, count := 0.0, 0
+	for _, T := range expiries {
+		for _, pt := range byExpiry[T] {
+			w := surface.TotalVariance(pt.k, T)
+			model := 0.0
+			if w > 0 {
+				model = math.Sqrt(w / T)
+			}
+			fit.Quotes = append(fit.Quotes, SurfaceQuoteFit{
+				Strike: pt.quote.Strike, Expiry: T, MarketVol: pt.vol, ModelVol: model, Error: model - pt.vol,
+			})
+			sumSq += (model - pt.vol) * (model - pt.vol)
+			count++
+			kMin = math.Min(kMin, pt.k)
+			kMax = math.Max(kMax, pt.k)
+		}
+	}
+
+	fit.Surface = surface
+	fit.RMSE = math.Sqrt(sumSq / float64(count))
+	fit.Arbitrage = surface.CheckArbitrage(kMin, kMax)
+
+	return fit, nil
+}
+
+func fitSVISlices(surface *VolSurface, expiries []float64, byExpiry map[float64][]surfacePoint) error {
+	for _, T := range expiries {
+		points := byExpiry[T]
+		if len(points) < 5 {
+			return fmt.Errorf("expiry %g has %d quotes; svi needs at least 5", T, len(points))
+		}
+
+		kMin, kMax, wMax := points[0].k, points[0].k, 0.0
+		atm := points[0]
+		for _, pt := range points {
+			kMin = math.Min(kMin, pt.k)
+			kMax = math.Max(kMax, pt.k)
+			wMax = math.Max(wMax, pt.vol*pt.vol*T)
+			if math.Abs(pt.k) < math.Abs(atm.k) {
+				atm = pt
+			}
+		}
+		span := math.Max(kMax-kMin, 0.1)
+
+		residuals := func(x []float64) []float64 {
+			p := SVIParams{A: x[0], B: x[1], Rho: x[2], M: x[3], Sigma: x[4]}
+			res := make([]float64, len(points)+1)
+			for i, pt := range points {
+				w := p.TotalVariance(pt.k)
+				if w <= 0 {
+					res[i] = -pt.vol - w
+					continue
+				}
+				res[i] = math.Sqrt(w/T) - pt.vol
+			}
+			// Keep the minimum variance a + bσ√(1-ρ²) non-negative
+			res[len(points)] = 10 * math.Max(0, -(p.A+p.B*p.Sigma*math.Sqrt(1-p.Rho*p.Rho)))
+			return res
+		}
+
+		wAtm := atm.vol * atm.vol * T
+		x0 := []float64{wAtm * 0.5, wAtm / (2 * 0.1), -0.3, 0, 0.1}
+		lower := []float64{-wMax, 1e-8, -0.999, kMin - span, 1e-4}
+		upper := []float64{wMax, 10, 0.999, kMax + span, 5}
+
+		result, err := opt.LevenbergMarquardt(residuals, x0, lower, upper, 1e-12, 500)
+		if err != nil {
+			return fmt.Errorf("expiry %g: %w", T, err)
+		}
+
+		x := result.X
+		surface.Slices = append(surface.Slices, SurfaceSlice{
+			Expiry:  T,
+			Forward: surface.Forward(T),
+			SVI:     &SVIParams{A: x[0], B: x[1], Rho: x[2], M: x[3], Sigma: x[4]},
+		})
+	}
+	return nil
+}
+
+// atmTotalVariance interpolates the quoted total variance linearly to k = 0
+func atmTotalVariance(points []surfacePoint, T float64) float64 {
+	sorted := append([]surfacePoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].k < sorted[j].k })
+
+	w := func(pt surfacePoint) float64 { return pt.vol * pt.vol * T }
+	if sorted[0].k >= 0 {
+		return w(sorted[0])
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].k >= 0 {
+			lo, hi := sorted[i-1], sorted[i]
+			return w(lo) + (w(hi)-w(lo))*(0-lo.k)/(hi.k-lo.k)
+		}
+	}
+	return w(sorted[len(sorted)-1])
+}
+
+func fitSSVI(surface *VolSurface, expiries []float64, byExpiry map[float64][]surfacePoint) error {
+	thetas := make([]float64, len(expiries))
+	total := 0
+	for i, T := range expiries {
+		if len(byExpiry[T]) < 2 {
+			return fmt.Errorf("expiry %g has fewer than 2 quotes", T)
+		}
+		thetas[i] = atmTotalVariance(byExpiry[T], T)
+		total += len(byExpiry[T])
+	}
+	if total < 3 {
+		return errors.New("ssvi needs at least 3 quotes")
+	}
+
+	residuals := func(x []float64) []float64 {
+		p := SSVIParams{Rho: x[0], Eta: x[1], Gamma: x[2]}
+		res := make([]float64, 0, total+1)
+		for i, T := range expiries {
+			for _, pt := range byExpiry[T] {
+				w := p.TotalVariance(pt.k, thetas[i])
+				res = append(res, math.Sqrt(math.Max(w, 0)/T)-pt.vol)
+			}
+		}
+		// Gatheral-Jacquier sufficient condition for no butterfly arbitrage
+		res = append(res, 10*math.Max(0, p.Eta*(1+math.Abs(p.Rho))-2))
+		return res
+	}
+
+	result, err := opt.LevenbergMarquardt(residuals,
+		[]float64{-0.5, 1, 0.5},
+		[]float64{-0.999, 1e-4, 0.01},
+		[]float64{0.999, 10, 1},
+		1e-12, 500)
+	if err != nil {
+		return err
+	}
+
+	surface.SSVI = &SSVIParams{Rho: result.X[0], Eta: result.X[1], Gamma: result.X[2]}
+	for i, T := range expiries {
+		surface.Slices = append(surface.Slices, SurfaceSlice{Expiry: T, Forward: surface.Forward(T), Theta: thetas[i]})
+	}
+	return nil
+}
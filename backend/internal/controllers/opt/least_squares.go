@@ -0,0 +1,182 @@
+package opt
+
+import (
+	"backend/internal/controllers/linear"
+	"errors"
+	"math"
+)
+
+type LeastSquaresResult struct {
+	X          []float64 `json:"x"`
+	Residuals  []float64 `json:"residuals"`
+	SumSquares float64   `json:"sum_squares"`
+	Iterations int       `json:"iterations"`
+	Converged  bool      `json:"converged"`
+}
+
+// LevenbergMarquardt minimises the sum of squared residuals with a forward-difference
+// Jacobian. lower and upper are optional box constraints enforced by projection.
+func LevenbergMarquardt(residuals func([]float64) []float64, x0, lower, upper []float64, tol float64, maxIter int) (LeastSquaresResult, error) {
+	n := len(x0)
+	if n == 0 {
+		return LeastSquaresResult{}, errors.New("initial guess cannot be empty")
+	}
+	if (lower != nil && len(lower) != n) || (upper != nil && len(upper) != n) {
+		return LeastSquaresResult{}, errors.New("bounds must match the number of parameters")
+	}
+	if tol <= 0 {
+		tol = 1e-10
+	}
+	if maxIter <= 0 {
+		maxIter = 200
+	}
+
+	project := func(x []float64) {
+		for i := range x {
+			if lower != nil && x[i] < lower[i] {
+				x[i] = lower[i]
+			}
+			if upper != nil && x[i] > upper[i] {
+				x[i] = upper[i]
+			}
+		}
+	}
+
+	x := append([]float64(nil), x0...)
+	project(x)
+
+	r := residuals(x)
+	if len(r) == 0 {
+		return LeastSquaresResult{}, errors.New("residual function returned no values")
+	}
+	cost := sumSquares(r)
+	if math.IsNaN(cost) || math.IsInf(cost, 0) {
+		return LeastSquaresResult{}, errors.New("residuals are not finite at the initial guess")
+	}
+
+	lambda := 1e-3
+	converged := false
+	iter := 0
+
+	for iter = 0; iter < maxIter; iter++ {
+		J := jacobian(residuals, x, r, lower, upper)
+
+		// Normal equations JᵀJ δ = -Jᵀr
+		JtJ := make([][]float64, n)
+		Jtr := make([]float64, n)
+		for i := 0; i < n; i++ {
+			JtJ[i] = make([]float64, n)
+			for j := 0; j < n; j++ {
+				for k := range r {
+					JtJ[i][j] += J[k][i] * J[k][j]
+				}
+			}
+			for k := range r {
+				Jtr[i] += J[k][i] * r[k]
+			}
+		}
+
+		improved := false
+		for attempt := 0; attempt < 20; attempt++ {
+			A := make([][]float64, n)
+			for i := 0; i < n; i++ {
+				A[i] = append([]float64(nil), JtJ[i]...)
+				A[i][i] += lambda * math.Max(JtJ[i][i], 1e-12)
+			}
+
+			inv, err := linear.Inverse(A)
+			if err != nil {
+				lambda *= 10
+				continue
+			}
+
+			candidate := make([]float64, n)
+			for i := 0; i < n; i++ {
+				step := 0.0
+				for j := 0; j < n; j++ {
+					step -= inv[i][j] * Jtr[j]
+				}
+				candidate[i] = x[i] + step
+			}
+			project(candidate)
+
+			rc := residuals(candidate)
+			costCandidate := sumSquares(rc)
+			if !math.IsNaN(costCandidate) && costCandidate < cost {
+				change := 0.0
+				for i := range x {
+					change = math.Max(change, math.Abs(candidate[i]-x[i])/(math.Abs(x[i])+1e-8))
+				}
+				relImprovement := (cost - costCandidate) / math.Max(cost, 1e-300)
+
+				x, r, cost = candidate, rc, costCandidate
+				lambda = math.Max(lambda/10, 1e-12)
+				improved = true
+
+				if change < tol || relImprovement < tol {
+					converged = true
+				}
+				break
+			}
+			lambda *= 10
+		}
+
+		if cost < 1e-30 {
+			converged = true
+		}
+		// No damped step reducing the cost is a stall, not convergence
+		if !improved || converged {
+			break
+		}
+	}
+
+	// A break leaves iter on the iteration that ended the fit; running out
+	// leaves it at maxIter
+	iterations := iter
+	if iter < maxIter {
+		iterations++
+	}
+
+	return LeastSquaresResult{
+		X:          x,
+		Residuals:  r,
+		SumSquares: cost,
+		Iterations: iterations,
+		Converged:  converged,
+	}, nil
+}
+
+func jacobian(residuals func([]float64) []float64, x, r, lower, upper []float64) [][]float64 {
+	J := make([][]float64, len(r))
+	for k := range J {
+		J[k] = make([]float64, len(x))
+	}
+
+	for i := range x {
+		h := 1e-7 * math.Max(math.Abs(x[i]), 1e-3)
+		// Step backwards when the forward step would leave the feasible box
+		if upper != nil && x[i]+h > upper[i] {
+			h = -h
+		}
+		if lower != nil && x[i]+h < lower[i] {
+			h = -h
+		}
+
+		shifted := append([]float64(nil), x...)
+		shifted[i] += h
+		rs := residuals(shifted)
+		for k := range r {
+			J[k][i] = (rs[k] - r[k]) / h
+		}
+	}
+
+	return J
+}
+
+func sumSquares(r []float64) float64 {
+	sum := 0.0
+	for _, v := range r {
+		sum += v * v
+	}
+	return sum
+}
@@ -5,9 +5,16 @@ import (
 	"math"
 )
 
-const goldenRatio = 1.618033988749895
+const (
+	goldenRatio = 1.618033988749895
 
-func GoldenSectionSearch(a, b, tol float64) (float64, error) {
+	// Each iteration shrinks the bracket by 0.618, so 200 reach well below
+	// float64 resolution for any bracket
+	maxGoldenIterations = 200
+)
+
+// GoldenSectionSearch minimizes f on [a, b], assuming it is unimodal there
+func GoldenSectionSearch(f func(float64) float64, a, b, tol float64) (float64, error) {
 	if b <= a {
 		return 0, errors.New("upper bound must be greater than lower bound")
 	}
@@ -16,15 +23,10 @@ func GoldenSectionSearch(a, b, tol float64) (float64, error) {
 		tol = 1e-6
 	}
 
-	// Simple quadratic function for demonstration: f(x) = (x-2)^2
-	f := func(x float64) float64 {
-		return (x - 2) * (x - 2)
-	}
-
 	c := b - (b-a)/goldenRatio
 	d := a + (b-a)/goldenRatio
 
-	for math.Abs(b-a) > tol {
+	for i := 0; i < maxGoldenIterations && math.Abs(b-a) > tol; i++ {
 		if f(c) < f(d) {
 			b = d
 		} else {
@@ -38,16 +40,7 @@ func GoldenSectionSearch(a, b, tol float64) (float64, error) {
 	return (a + b) / 2, nil
 }
 
-func NewtonRaphson(x0, tol float64, maxIter int) (float64, error) {
-	// Example: finding root of f(x) = x^2 - 2 (sqrt of 2)
-	f := func(x float64) float64 {
-		return x*x - 2
-	}
-
-	df := func(x float64) float64 {
-		return 2 * x
-	}
-
+func NewtonRaphson(f, df func(float64) float64, x0, tol float64, maxIter int) (float64, error) {
 	x := x0
 
 	for i := 0; i < maxIter; i++ {
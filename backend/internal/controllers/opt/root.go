@@ -0,0 +1,95 @@
+package opt
+
+import (
+	"errors"
+	"math"
+)
+
+// BrentRoot finds a root of f inside [a, b], which must bracket a sign change
+func BrentRoot(f func(float64) float64, a, b, tol float64) (float64, error) {
+	if tol <= 0 {
+		tol = 1e-12
+	}
+
+	fa, fb := f(a), f(b)
+	if math.IsNaN(fa) || math.IsNaN(fb) {
+		return 0, errors.New("function is not defined at the bracket end points")
+	}
+	if fa == 0 {
+		return a, nil
+	}
+	if fb == 0 {
+		return b, nil
+	}
+	if fa*fb > 0 {
+		return 0, errors.New("root is not bracketed")
+	}
+
+	c, fc := a, fa
+	d := b - a
+	e := d
+
+	for i := 0; i < 200; i++ {
+		if fb*fc > 0 {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		tol1 := 2*1e-16*math.Abs(b) + 0.5*tol
+		xm := 0.5 * (c - b)
+		if math.Abs(xm) <= tol1 || fb == 0 {
+			return b, nil
+		}
+
+		if math.Abs(e) >= tol1 && math.Abs(fa) > math.Abs(fb) {
+			// Inverse quadratic interpolation (secant when only two points)
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * xm * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*xm*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+
+			if 2*p < math.Min(3*xm*q-math.Abs(tol1*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = xm
+				e = d
+			}
+		} else {
+			// Bisection
+			d = xm
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol1 {
+			b += d
+		} else if xm > 0 {
+			b += tol1
+		} else {
+			b -= tol1
+		}
+		fb = f(b)
+		if math.IsNaN(fb) {
+			return 0, errors.New("function returned NaN during root search")
+		}
+	}
+
+	return 0, errors.New("maximum iterations reached")
+}
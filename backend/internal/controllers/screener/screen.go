@@ -0,0 +1,275 @@
+package screener
+
+import (
+	"backend/internal/controllers/expr"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+type TransformKind string
+
+const (
+	Rank       TransformKind = "rank"       // 1 for the smallest value, or the largest when descending
+	Percentile TransformKind = "percentile" // In [0, 1], ties share their average rank
+	ZScore     TransformKind = "zscore"
+)
+
+// Transform adds a cross-sectional field computed over every security that has
+// Field. As defaults to the field name with a _rank, _pct or _z suffix.
+type Transform struct {
+	Field      string        `json:"field"`
+	Kind       TransformKind `json:"kind"`
+	As         string        `json:"as"`
+	Descending bool          `json:"descending"`
+}
+
+// Column is a per-security field derived from an expression, such as
+// earnings_yield = 1 / pe
+type Column struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// Screen runs in order: columns, transforms, the filter, then sorting. Filter
+// and SortBy are expressions over any field, so transforms can be screened on.
+type Screen struct {
+	Filter     string      `json:"filter"`
+	Columns    []Column    `json:"columns"`
+	Transforms []Transform `json:"transforms"`
+	SortBy     string      `json:"sort_by"`
+	Descending bool        `json:"descending"`
+	Limit      int         `json:"limit"`
+	Fields     []string    `json:"fields"` // Returned fields; defaults to those the screen uses, * for all
+}
+
+type Match struct {
+	Rank   int                `json:"rank"`
+	Ticker string             `json:"ticker"`
+	Values map[string]float64 `json:"values"`
+}
+
+type ScreenResult struct {
+	Universe int     `json:"universe"`
+	Matched  int     `json:"matched"`
+	Missing  int     `json:"missing"` // Excluded because a filter field was missing
+	Matches  []Match `json:"matches"`
+}
+
+func (t Transform) name() string {
+	if t.As != "" {
+		return t.As
+	}
+	suffix := map[TransformKind]string{Rank: "_rank", Percentile: "_pct", ZScore: "_z"}[t.Kind]
+	return t.Field + suffix
+}
+
+func finite(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+
+// apply writes the transform into fields for every security that has the input
+func (t Transform) apply(fields []map[string]float64) error {
+	switch t.Kind {
+	case Rank, Percentile, ZScore:
+	default:
+		return errors.New("transform kind must be rank, percentile or zscore")
+	}
+	var idx []int
+	for i, f := range fields {
+		if _, ok := f[t.Field]; ok {
+			idx = append(idx, i)
+		}
+	}
+	out := t.name()
+	n := len(idx)
+	if n == 0 {
+		return nil
+	}
+
+	switch t.Kind {
+	case Rank, Percentile:
+		sort.SliceStable(idx, func(a, b int) bool {
+			va, vb := fields[idx[a]][t.Field], fields[idx[b]][t.Field]
+			if t.Descending {
+				return va > vb
+			}
+			return va < vb
+		})
+		for start := 0; start < n; {
+			end := start + 1
+			for end < n && fields[idx[end]][t.Field] == fields[idx[start]][t.Field] {
+				end++
+			}
+			// Ties share the average of their 1-based positions
+			rank := float64(start+end+1) / 2
+			for _, i := range idx[start:end] {
+				if t.Kind == Rank {
+					fields[i][out] = rank
+				} else if n == 1 {
+					fields[i][out] = 0.5
+				} else {
+					fields[i][out] = (rank - 1) / float64(n-1)
+				}
+			}
+			start = end
+		}
+	case ZScore:
+		mean := 0.0
+		for _, i := range idx {
+			mean += fields[i][t.Field]
+		}
+		mean /= float64(n)
+		variance := 0.0
+		for _, i := range idx {
+			d := fields[i][t.Field] - mean
+			variance += d * d
+		}
+		if n < 2 || variance == 0 {
+			return nil
+		}
+		sd := math.Sqrt(variance / float64(n-1))
+		for _, i := range idx {
+			fields[i][out] = (fields[i][t.Field] - mean) / sd
+		}
+	}
+	return nil
+}
+
+func lookupIn(fields map[string]float64) expr.Lookup {
+	return func(name string) (float64, bool) {
+		v, ok := fields[name]
+		return v, ok
+	}
+}
+
+// Run screens the universe without modifying it
+func Run(u *Universe, s Screen) (ScreenResult, error) {
+	if err := u.validate(); err != nil {
+		return ScreenResult{}, err
+	}
+	if len(s.Columns) > 50 || len(s.Transforms) > 50 {
+		return ScreenResult{}, errors.New("at most 50 columns and 50 transforms are allowed")
+	}
+	fields := make([]map[string]float64, len(u.Securities))
+	for i, sec := range u.Securities {
+		fields[i] = make(map[string]float64, len(sec.Fields)+len(s.Columns)+len(s.Transforms))
+		for k, v := range sec.Fields {
+			fields[i][k] = v
+		}
+	}
+	used := map[string]bool{}
+
+	for _, col := range s.Columns {
+		if col.Name == "" {
+			return ScreenResult{}, errors.New("columns need a name")
+		}
+		e, err := expr.Parse(col.Expression)
+		if err != nil {
+			return ScreenResult{}, errors.New("column " + col.Name + ": " + err.Error())
+		}
+		for _, f := range fields {
+			if v, err := e.Eval(lookupIn(f)); err == nil && finite(v) {
+				f[col.Name] = v
+			}
+		}
+		used[col.Name] = true
+	}
+	for _, t := range s.Transforms {
+		if t.Field == "" {
+			return ScreenResult{}, errors.New("transforms need a field")
+		}
+		if err := t.apply(fields); err != nil {
+			return ScreenResult{}, err
+		}
+		used[t.Field], used[t.name()] = true, true
+	}
+
+	result := ScreenResult{Universe: len(u.Securities), Matches: []Match{}}
+	var filter, sortBy *expr.Expr
+	var err error
+	if strings.TrimSpace(s.Filter) != "" {
+		if filter, err = expr.Parse(s.Filter); err != nil {
+			return ScreenResult{}, errors.New("filter: " + err.Error())
+		}
+		for _, v := range filter.Variables() {
+			used[v] = true
+		}
+	}
+	if strings.TrimSpace(s.SortBy) != "" {
+		if sortBy, err = expr.Parse(s.SortBy); err != nil {
+			return ScreenResult{}, errors.New("sort_by: " + err.Error())
+		}
+		for _, v := range sortBy.Variables() {
+			used[v] = true
+		}
+	}
+
+	type candidate struct {
+		index  int
+		key    float64
+		hasKey bool
+	}
+	var matched []candidate
+	for i, f := range fields {
+		if filter != nil {
+			v, err := filter.Eval(lookupIn(f))
+			var missing *expr.MissingVariableError
+			if errors.As(err, &missing) {
+				result.Missing++
+				continue
+			}
+			if err != nil || v == 0 || math.IsNaN(v) {
+				continue
+			}
+		}
+		c := candidate{index: i}
+		if sortBy != nil {
+			if v, err := sortBy.Eval(lookupIn(f)); err == nil && finite(v) {
+				c.key, c.hasKey = v, true
+			}
+		}
+		matched = append(matched, c)
+	}
+	result.Matched = len(matched)
+
+	// Securities without a sort value go last; ties keep ticker order
+	sort.SliceStable(matched, func(a, b int) bool {
+		ca, cb := matched[a], matched[b]
+		if sortBy != nil && ca.hasKey != cb.hasKey {
+			return ca.hasKey
+		}
+		if sortBy != nil && ca.key != cb.key {
+			if s.Descending {
+				return ca.key > cb.key
+			}
+			return ca.key < cb.key
+		}
+		return u.Securities[ca.index].Ticker < u.Securities[cb.index].Ticker
+	})
+	if s.Limit > 0 && len(matched) > s.Limit {
+		matched = matched[:s.Limit]
+	}
+
+	all := len(s.Fields) == 1 && s.Fields[0] == "*"
+	for rank, c := range matched {
+		values := map[string]float64{}
+		switch {
+		case all:
+			values = fields[c.index]
+		case len(s.Fields) > 0:
+			for _, name := range s.Fields {
+				if v, ok := fields[c.index][name]; ok {
+					values[name] = v
+				}
+			}
+		default:
+			for name := range used {
+				if v, ok := fields[c.index][name]; ok {
+					values[name] = v
+				}
+			}
+		}
+		result.Matches = append(result.Matches, Match{Rank: rank + 1, Ticker: u.Securities[c.index].Ticker, Values: values})
+	}
+	return result, nil
+}
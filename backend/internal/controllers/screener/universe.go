@@ -0,0 +1,227 @@
+package screener
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	maxUniverses  = 100
+	maxSecurities = 50_000
+	maxFields     = 500
+)
+
+// Security is one ticker's fundamental and technical fields, such as pe or rsi14
+type Security struct {
+	Ticker string             `json:"ticker"`
+	Fields map[string]float64 `json:"fields"`
+}
+
+type Universe struct {
+	Name       string     `json:"name"`
+	Securities []Security `json:"securities"`
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Universe{}
+)
+
+// FieldName lower-cases a column header and replaces anything an expression
+// cannot reference with underscores, so "P/E Ratio" becomes p_e_ratio
+func FieldName(header string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(header)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '.' {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
+
+func isTickerColumn(name string) bool { return name == "ticker" || name == "symbol" }
+
+func (u *Universe) validate() error {
+	if strings.TrimSpace(u.Name) == "" {
+		return errors.New("universe name is required")
+	}
+	if len(u.Securities) == 0 || len(u.Securities) > maxSecurities {
+		return fmt.Errorf("a universe needs between 1 and %d securities", maxSecurities)
+	}
+	seen := map[string]bool{}
+	for _, s := range u.Securities {
+		if s.Ticker == "" {
+			return errors.New("every security needs a ticker")
+		}
+		if seen[s.Ticker] {
+			return errors.New("duplicate ticker: " + s.Ticker)
+		}
+		if len(s.Fields) > maxFields {
+			return fmt.Errorf("security %s has more than %d fields", s.Ticker, maxFields)
+		}
+		seen[s.Ticker] = true
+	}
+	return nil
+}
+
+// ParseCSV reads a header row with a ticker (or symbol) column followed by one
+// row per security. Empty, NA, NaN and null cells are treated as missing.
+func ParseCSV(name string, data []byte) (*Universe, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, errors.New("CSV needs a header row")
+	}
+	columns := make([]string, len(header))
+	tickerCol := -1
+	for i, h := range header {
+		columns[i] = FieldName(h)
+		if isTickerColumn(columns[i]) && tickerCol < 0 {
+			tickerCol = i
+		}
+	}
+	if tickerCol < 0 {
+		return nil, errors.New("CSV needs a ticker or symbol column")
+	}
+
+	u := &Universe{Name: name}
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s := Security{Ticker: strings.TrimSpace(record[tickerCol]), Fields: map[string]float64{}}
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if i == tickerCol || columns[i] == "" || cell == "" {
+				continue
+			}
+			switch strings.ToLower(cell) {
+			case "na", "n/a", "nan", "null", "-":
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSuffix(cell, "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s is not a number: %q", line, columns[i], cell)
+			}
+			if strings.HasSuffix(cell, "%") {
+				v /= 100
+			}
+			s.Fields[columns[i]] = v
+		}
+		u.Securities = append(u.Securities, s)
+	}
+	return u, u.validate()
+}
+
+// ParseJSON reads an array of flat objects keyed by field, each with a ticker
+// or symbol. Booleans count as 1 or 0; nulls and other strings are skipped.
+func ParseJSON(name string, data []byte) (*Universe, error) {
+	var rows []map[string]any
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, errors.New("JSON universe must be an array of objects")
+	}
+	u := &Universe{Name: name}
+	for _, row := range rows {
+		s := Security{Fields: map[string]float64{}}
+		for key, value := range row {
+			field := FieldName(key)
+			switch v := value.(type) {
+			case string:
+				if isTickerColumn(field) && s.Ticker == "" {
+					s.Ticker = strings.TrimSpace(v)
+				}
+			case float64:
+				s.Fields[field] = v
+			case bool:
+				s.Fields[field] = 0
+				if v {
+					s.Fields[field] = 1
+				}
+			}
+		}
+		u.Securities = append(u.Securities, s)
+	}
+	return u, u.validate()
+}
+
+// Register stores a universe under a name not already taken, so one client
+// cannot replace another's universe; delete it first to reload. Universes do
+// not survive a restart.
+func Register(u *Universe) error {
+	if err := u.validate(); err != nil {
+		return err
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[u.Name]; ok {
+		return errors.New("universe " + u.Name + " already exists; delete it before loading it again")
+	}
+	if len(registry) >= maxUniverses {
+		return errors.New("universe store is full; delete unused universes first")
+	}
+	registry[u.Name] = u
+	return nil
+}
+
+func Lookup(name string) (*Universe, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	u, ok := registry[name]
+	if !ok {
+		return nil, errors.New("unknown universe: " + name)
+	}
+	return u, nil
+}
+
+func Delete(name string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	_, ok := registry[name]
+	delete(registry, name)
+	return ok
+}
+
+// Names lists stored universes with their sizes
+func Names() map[string]int {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make(map[string]int, len(registry))
+	for name, u := range registry {
+		names[name] = len(u.Securities)
+	}
+	return names
+}
+
+// Fields lists every field present on at least one security
+func (u *Universe) Fields() []string {
+	seen := map[string]bool{}
+	for _, s := range u.Securities {
+		for f := range s.Fields {
+			seen[f] = true
+		}
+	}
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
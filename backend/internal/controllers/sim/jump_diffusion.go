@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+)
+
+// MertonJumpPaths simulates GBM with compound-Poisson jumps whose log sizes are
+// N(jumpMean, jumpVol²). mu is the total expected return; the jump drift is compensated.
+func MertonJumpPaths(S0, mu, sigma, lambda, jumpMean, jumpVol, T float64, steps, paths int, streams *Streams) ([][]float64, error) {
+	if jumpVol < 0 {
+		return nil, errors.New("jump vol cannot be negative")
+	}
+
+	compensator := math.Exp(jumpMean+jumpVol*jumpVol/2) - 1
+	return jumpDiffusionPaths(S0, mu, sigma, lambda, compensator, T, steps, paths, streams, func(rng *rand.Rand) float64 {
+		return jumpMean + jumpVol*rng.NormFloat64()
+	})
+}
+
+// KouJumpPaths simulates GBM with double-exponential jumps: upward with probability
+// p and rate eta1, downward with rate eta2.
+func KouJumpPaths(S0, mu, sigma, lambda, p, eta1, eta2, T float64, steps, paths int, streams *Streams) ([][]float64, error) {
+	if p < 0 || p > 1 || eta1 <= 1 || eta2 <= 0 {
+		return nil, errors.New("invalid double-exponential jump parameters")
+	}
+
+	compensator := p*eta1/(eta1-1) + (1-p)*eta2/(eta2+1) - 1
+	return jumpDiffusionPaths(S0, mu, sigma, lambda, compensator, T, steps, paths, streams, func(rng *rand.Rand) float64 {
+		if rng.Float64() < p {
+			return rng.ExpFloat64() / eta1
+		}
+		return -rng.ExpFloat64() / eta2
+	})
+}
+
+func jumpDiffusionPaths(S0, mu, sigma, lambda, compensator, T float64, steps, paths int, streams *Streams, jump func(*rand.Rand) float64) ([][]float64, error) {
+	if steps <= 0 || paths <= 0 {
+		return nil, errors.New("steps and paths must be positive")
+	}
+	if T <= 0 || sigma < 0 || S0 <= 0 || lambda < 0 {
+		return nil, errors.New("invalid parameters")
+	}
+
+	dt := T / float64(steps)
+	drift := (mu - lambda*compensator - 0.5*sigma*sigma) * dt
+	diffusion := sigma * math.Sqrt(dt)
+	jumpProb := math.Exp(-lambda * dt)
+
+	result := make([][]float64, paths)
+
+	for i := 0; i < paths; i++ {
+		rng := streams.Stream(i)
+		path := make([]float64, steps+1)
+		path[0] = S0
+
+		for j := 1; j <= steps; j++ {
+			z := rng.NormFloat64()
+			logReturn := drift + diffusion*z
+
+			// Knuth's Poisson sampler; λdt is small so this rarely loops
+			for u := rng.Float64(); u > jumpProb; u *= rng.Float64() {
+				logReturn += jump(rng)
+			}
+
+			path[j] = path[j-1] * math.Exp(logReturn)
+		}
+		result[i] = path
+	}
+	return result, nil
+}
@@ -3,8 +3,6 @@ package sim
 import (
 	"errors"
 	"math"
-	"math/rand"
-	"time"
 )
 
 type MonteCarloResult struct {
@@ -14,12 +12,12 @@ type MonteCarloResult struct {
 	Samples    []float64 `json:"samples"`
 }
 
-func MonteCarlo(numSamples int) (MonteCarloResult, error) {
+func MonteCarlo(numSamples int, streams *Streams) (MonteCarloResult, error) {
 	if numSamples <= 0 {
 		return MonteCarloResult{}, errors.New("number of samples must be positive")
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng := streams.Stream(0)
 
 	samples := make([]float64, numSamples)
 	insideCircle := 0
@@ -57,7 +55,46 @@ func MonteCarlo(numSamples int) (MonteCarloResult, error) {
 	}, nil
 }
 
-func GeometricBrownianMotion(S0, mu, sigma, T float64, steps, paths int) ([][]float64, error) {
+// MonteCarloIntegral estimates the integral of f over [a, b] as (b-a) times the
+// mean of f at uniform draws
+func MonteCarloIntegral(f func(float64) float64, a, b float64, numSamples int, streams *Streams) (MonteCarloResult, error) {
+	if numSamples < 2 {
+		return MonteCarloResult{}, errors.New("at least 2 samples are required")
+	}
+	if !(b > a) || math.IsInf(b-a, 0) {
+		return MonteCarloResult{}, errors.New("upper bound must be finite and greater than lower bound")
+	}
+
+	rng := streams.Stream(0)
+
+	samples := make([]float64, numSamples)
+	sum := 0.0
+	for i := range samples {
+		samples[i] = (b - a) * f(a+(b-a)*rng.Float64())
+		sum += samples[i]
+	}
+
+	mean := sum / float64(numSamples)
+	var variance float64
+	for _, sample := range samples {
+		diff := sample - mean
+		variance += diff * diff
+	}
+	variance /= float64(numSamples - 1)
+	stdDev := math.Sqrt(variance)
+
+	marginError := 1.96 * stdDev / math.Sqrt(float64(numSamples))
+	confidence := []float64{mean - marginError, mean + marginError}
+
+	return MonteCarloResult{
+		Mean:       mean,
+		StdDev:     stdDev,
+		Confidence: confidence,
+		Samples:    samples[:min(100, len(samples))],
+	}, nil
+}
+
+func GeometricBrownianMotion(S0, mu, sigma, T float64, steps, paths int, streams *Streams) ([][]float64, error) {
 	if steps <= 0 || paths <= 0 {
 		return nil, errors.New("steps and paths must be positive")
 	}
@@ -65,8 +102,6 @@ func GeometricBrownianMotion(S0, mu, sigma, T float64, steps, paths int) ([][]fl
 		return nil, errors.New("invalid parameters")
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-
 	dt := T / float64(steps)
 	drift := (mu - 0.5*sigma*sigma) * dt
 	diffusion := sigma * math.Sqrt(dt)
@@ -74,6 +109,7 @@ func GeometricBrownianMotion(S0, mu, sigma, T float64, steps, paths int) ([][]fl
 	result := make([][]float64, paths)
 
 	for i := 0; i < paths; i++ {
+		rng := streams.Stream(i)
 		path := make([]float64, steps+1)
 		path[0] = S0
 
@@ -86,7 +122,43 @@ func GeometricBrownianMotion(S0, mu, sigma, T float64, steps, paths int) ([][]fl
 	return result, nil
 }
 
-func OptionPricingMonteCarlo(S0, K, T, r, sigma float64, numSims int, isCall bool) (float64, error) {
+// LocalVolatilityPaths generalises GeometricBrownianMotion to a state-dependent
+// volatility σ(S, t), evaluated at the start of each log-Euler step, and a
+// time-varying drift where mu(t0, t1) is the average rate over a step.
+func LocalVolatilityPaths(S0 float64, mu func(t0, t1 float64) float64, T float64, steps, paths int, sigma func(S, t float64) float64, streams *Streams) ([][]float64, error) {
+	if steps <= 0 || paths <= 0 {
+		return nil, errors.New("steps and paths must be positive")
+	}
+	if T <= 0 || S0 <= 0 || sigma == nil || mu == nil {
+		return nil, errors.New("invalid parameters")
+	}
+
+	dt := T / float64(steps)
+	sqrtDt := math.Sqrt(dt)
+
+	drift := make([]float64, steps+1)
+	for j := 1; j <= steps; j++ {
+		drift[j] = mu(float64(j-1)*dt, float64(j)*dt)
+	}
+
+	result := make([][]float64, paths)
+
+	for i := 0; i < paths; i++ {
+		rng := streams.Stream(i)
+		path := make([]float64, steps+1)
+		path[0] = S0
+
+		for j := 1; j <= steps; j++ {
+			vol := sigma(path[j-1], float64(j-1)*dt)
+			z := rng.NormFloat64()
+			path[j] = path[j-1] * math.Exp((drift[j]-0.5*vol*vol)*dt+vol*sqrtDt*z)
+		}
+		result[i] = path
+	}
+	return result, nil
+}
+
+func OptionPricingMonteCarlo(S0, K, T, r, sigma float64, numSims int, isCall bool, streams *Streams) (float64, error) {
 	if numSims <= 0 {
 		return 0, errors.New("number of simulations must be positive")
 	}
@@ -94,7 +166,7 @@ func OptionPricingMonteCarlo(S0, K, T, r, sigma float64, numSims int, isCall boo
 		return 0, errors.New("invalid parameters")
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng := streams.Stream(0)
 
 	var payoffSum float64
 	drift := (r - 0.5*sigma*sigma) * T
@@ -0,0 +1,185 @@
+package sim
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Source produces uniformly distributed 64-bit values. It matches
+// math/rand/v2's Source, so any generator there can be registered.
+type Source interface {
+	Uint64() uint64
+}
+
+// SourceFactory builds the generator for one stream of a seed
+type SourceFactory func(seed, stream uint64) Source
+
+const DefaultGenerator = "pcg"
+
+var (
+	generatorsMu sync.RWMutex
+	generators   = map[string]SourceFactory{}
+)
+
+func init() {
+	RegisterGenerator("pcg", func(seed, stream uint64) Source {
+		return rand.NewPCG(streamState(seed, stream))
+	})
+	RegisterGenerator("xoshiro", newXoshiro)
+	RegisterGenerator("philox", newPhilox)
+}
+
+// RegisterGenerator adds or replaces a named generator
+func RegisterGenerator(name string, factory SourceFactory) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	generators[name] = factory
+}
+
+func Generators() []string {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RandomSeed draws a fresh seed for callers that do not supply one. It fits in
+// 53 bits so it survives a round trip through JSON clients that use doubles.
+func RandomSeed() uint64 {
+	const mask = 1<<53 - 1
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return uint64(time.Now().UnixNano()) & mask
+	}
+	return binary.LittleEndian.Uint64(buf[:]) & mask
+}
+
+// Streams hands out one reproducible random stream per path, so a path's draws
+// depend only on the seed and its index, not on how many paths run or in which
+// order. Philox streams are distinct counter ranges; PCG and xoshiro streams are
+// seeded from a hash of the seed and index.
+type Streams struct {
+	generator string
+	seed      uint64
+	factory   SourceFactory
+}
+
+// NewStreams uses DefaultGenerator when generator is empty
+func NewStreams(generator string, seed uint64) (*Streams, error) {
+	if generator == "" {
+		generator = DefaultGenerator
+	}
+	generatorsMu.RLock()
+	factory, ok := generators[generator]
+	generatorsMu.RUnlock()
+	if !ok {
+		return nil, errors.New("unknown generator: " + generator)
+	}
+	return &Streams{generator: generator, seed: seed, factory: factory}, nil
+}
+
+func (s *Streams) Seed() uint64      { return s.seed }
+func (s *Streams) Generator() string { return s.generator }
+
+func (s *Streams) Stream(i int) *rand.Rand {
+	return rand.New(s.factory(s.seed, uint64(i)))
+}
+
+func splitmix64(x *uint64) uint64 {
+	*x += 0x9e3779b97f4a7c15
+	z := *x
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// streamState expands (seed, stream) into two well-mixed words
+func streamState(seed, stream uint64) (uint64, uint64) {
+	x := seed
+	x = splitmix64(&x) ^ stream
+	return splitmix64(&x), splitmix64(&x)
+}
+
+// xoshiro is xoshiro256** by Blackman and Vigna
+type xoshiro struct {
+	s [4]uint64
+}
+
+func newXoshiro(seed, stream uint64) Source {
+	x := seed
+	x = splitmix64(&x) ^ stream
+	g := &xoshiro{}
+	for i := range g.s {
+		g.s[i] = splitmix64(&x)
+	}
+	return g
+}
+
+func (g *xoshiro) Uint64() uint64 {
+	s := &g.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// philox is the counter-based Philox4x32-10 of Salmon et al. The key is the
+// seed and the upper half of the 128-bit counter is the stream index, so
+// streams never overlap.
+type philox struct {
+	key    [2]uint32
+	stream uint64
+	block  uint64
+	buf    [4]uint32
+	next   int
+}
+
+func newPhilox(seed, stream uint64) Source {
+	return &philox{key: [2]uint32{uint32(seed), uint32(seed >> 32)}, stream: stream, next: 4}
+}
+
+func (g *philox) Uint64() uint64 {
+	if g.next >= 4 {
+		g.buf = philox4x32(
+			[4]uint32{uint32(g.block), uint32(g.block >> 32), uint32(g.stream), uint32(g.stream >> 32)},
+			g.key,
+		)
+		g.block++
+		g.next = 0
+	}
+	v := uint64(g.buf[g.next])<<32 | uint64(g.buf[g.next+1])
+	g.next += 2
+	return v
+}
+
+func philox4x32(ctr [4]uint32, key [2]uint32) [4]uint32 {
+	const (
+		m0, m1 = 0xD2511F53, 0xCD9E8D57
+		w0, w1 = 0x9E3779B9, 0xBB67AE85
+	)
+	for round := 0; round < 10; round++ {
+		if round > 0 {
+			key[0] += w0
+			key[1] += w1
+		}
+		hi0, lo0 := bits.Mul32(m0, ctr[0])
+		hi1, lo1 := bits.Mul32(m1, ctr[2])
+		ctr = [4]uint32{hi1 ^ ctr[1] ^ key[0], lo1, hi0 ^ ctr[3] ^ key[1], lo0}
+	}
+	return ctr
+}
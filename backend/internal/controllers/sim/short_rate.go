@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"errors"
+	"math"
+)
+
+// VasicekPaths samples dr = κ(θ - r)dt + σ dW from its exact Gaussian transition
+func VasicekPaths(r0, kappa, theta, sigma, T float64, steps, paths int, streams *Streams) ([][]float64, error) {
+	if steps <= 0 || paths <= 0 {
+		return nil, errors.New("steps and paths must be positive")
+	}
+	if T <= 0 || kappa <= 0 || sigma <= 0 {
+		return nil, errors.New("invalid parameters")
+	}
+	return ornsteinUhlenbeckPaths(r0, kappa, sigma, T, steps, paths, streams, func(float64) float64 { return theta }, func(x, _ float64) float64 { return x })
+}
+
+// HullWhitePaths samples r(t) = x(t) + φ(t), where x is a zero-mean OU process
+// and shift is φ, which fits the model to the initial curve
+func HullWhitePaths(a, sigma, T float64, steps, paths int, shift func(t float64) float64, streams *Streams) ([][]float64, error) {
+	if steps <= 0 || paths <= 0 {
+		return nil, errors.New("steps and paths must be positive")
+	}
+	if T <= 0 || a <= 0 || sigma <= 0 || shift == nil {
+		return nil, errors.New("invalid parameters")
+	}
+	return ornsteinUhlenbeckPaths(0, a, sigma, T, steps, paths, streams, func(float64) float64 { return 0 }, func(x, t float64) float64 { return x + shift(t) })
+}
+
+func ornsteinUhlenbeckPaths(x0, kappa, sigma, T float64, steps, paths int, streams *Streams, mean func(t float64) float64, observe func(x, t float64) float64) ([][]float64, error) {
+	dt := T / float64(steps)
+	decay := math.Exp(-kappa * dt)
+	stdev := sigma * math.Sqrt(-math.Expm1(-2*kappa*dt)/(2*kappa))
+
+	result := make([][]float64, paths)
+
+	for i := 0; i < paths; i++ {
+		rng := streams.Stream(i)
+		path := make([]float64, steps+1)
+		x := x0
+		path[0] = observe(x, 0)
+
+		for j := 1; j <= steps; j++ {
+			theta := mean(float64(j-1) * dt)
+			x = theta + (x-theta)*decay + stdev*rng.NormFloat64()
+			path[j] = observe(x, float64(j)*dt)
+		}
+		result[i] = path
+	}
+	return result, nil
+}
+
+// CIRPaths samples dr = κ(θ - r)dt + σ√r dW with full-truncation Euler, which
+// keeps the drift and diffusion well defined when the discretised rate dips below zero
+func CIRPaths(r0, kappa, theta, sigma, T float64, steps, paths int, streams *Streams) ([][]float64, error) {
+	if steps <= 0 || paths <= 0 {
+		return nil, errors.New("steps and paths must be positive")
+	}
+	if T <= 0 || kappa <= 0 || theta <= 0 || sigma <= 0 || r0 < 0 {
+		return nil, errors.New("invalid parameters")
+	}
+
+	dt := T / float64(steps)
+	sqrtDt := math.Sqrt(dt)
+
+	result := make([][]float64, paths)
+
+	for i := 0; i < paths; i++ {
+		rng := streams.Stream(i)
+		path := make([]float64, steps+1)
+		path[0] = r0
+		x := r0
+
+		for j := 1; j <= steps; j++ {
+			pos := math.Max(x, 0)
+			x += kappa*(theta-pos)*dt + sigma*math.Sqrt(pos)*sqrtDt*rng.NormFloat64()
+			path[j] = math.Max(x, 0)
+		}
+		result[i] = path
+	}
+	return result, nil
+}
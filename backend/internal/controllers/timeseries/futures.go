@@ -0,0 +1,221 @@
+package timeseries
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Carry holds cost-of-carry inputs with continuously compounded rates. Yield is a
+// dividend or convenience yield, StorageCost a proportional cost, and Income the
+// present value of discrete income paid before delivery.
+type Carry struct {
+	Spot        float64 `json:"spot_price"`
+	Rate        float64 `json:"risk_free_rate"`
+	Yield       float64 `json:"yield"`
+	StorageCost float64 `json:"storage_cost"`
+	Income      float64 `json:"income"`
+}
+
+type FuturesQuote struct {
+	Expiry float64 `json:"expiry"`
+	Price  float64 `json:"price"` // Optional market price
+}
+
+type FuturesValue struct {
+	Expiry      float64  `json:"expiry"`
+	FairValue   float64  `json:"fair_value"`
+	Price       *float64 `json:"price,omitempty"`
+	Basis       *float64 `json:"basis,omitempty"`      // Spot less futures
+	Mispricing  *float64 `json:"mispricing,omitempty"` // Futures less fair value
+	ImpliedRepo *float64 `json:"implied_repo,omitempty"`
+}
+
+type RollYield struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Yield float64 `json:"yield"` // Annualised, positive in backwardation
+}
+
+func (c Carry) validate() error {
+	if c.Spot <= 0 || c.Income < 0 || c.Income >= c.Spot {
+		return errors.New("spot price must be positive and income between 0 and spot")
+	}
+	return nil
+}
+
+// FairValue is F = (S - I) e^{(r + u - y)T}
+func (c Carry) FairValue(T float64) (float64, error) {
+	if err := c.validate(); err != nil {
+		return 0, err
+	}
+	if T < 0 {
+		return 0, errors.New("expiry cannot be negative")
+	}
+	return (c.Spot - c.Income) * math.Exp((c.Rate+c.StorageCost-c.Yield)*T), nil
+}
+
+// ImpliedRepo is the financing rate at which a cash-and-carry trade at the
+// market futures price breaks even
+func (c Carry) ImpliedRepo(price, T float64) (float64, error) {
+	if err := c.validate(); err != nil {
+		return 0, err
+	}
+	if price <= 0 || T <= 0 {
+		return 0, errors.New("futures price and expiry must be positive")
+	}
+	return math.Log(price/(c.Spot-c.Income))/T - c.StorageCost + c.Yield, nil
+}
+
+// Analyze values each quote and, where market prices are given, the basis,
+// implied repo and the roll yield between consecutive expiries
+func (c Carry) Analyze(quotes []FuturesQuote) ([]FuturesValue, []RollYield, error) {
+	sorted := append([]FuturesQuote(nil), quotes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Expiry < sorted[j].Expiry })
+
+	values := make([]FuturesValue, len(sorted))
+	rolls := []RollYield{}
+	for i, q := range sorted {
+		if q.Price < 0 {
+			return nil, nil, errors.New("futures prices cannot be negative")
+		}
+		fair, err := c.FairValue(q.Expiry)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i] = FuturesValue{Expiry: q.Expiry, FairValue: fair}
+		if q.Price == 0 {
+			continue
+		}
+		price, basis, mispricing := q.Price, c.Spot-q.Price, q.Price-fair
+		values[i].Price, values[i].Basis, values[i].Mispricing = &price, &basis, &mispricing
+		if q.Expiry > 0 {
+			repo, err := c.ImpliedRepo(q.Price, q.Expiry)
+			if err != nil {
+				return nil, nil, err
+			}
+			values[i].ImpliedRepo = &repo
+		}
+		if i > 0 && sorted[i-1].Price > 0 && q.Expiry > sorted[i-1].Expiry {
+			prev := sorted[i-1]
+			rolls = append(rolls, RollYield{From: prev.Expiry, To: q.Expiry, Yield: CalendarRollYield(prev.Price, q.Price, prev.Expiry, q.Expiry)})
+		}
+	}
+	return values, rolls, nil
+}
+
+// CalendarRollYield is ln(near/far)/(T2 - T1), the annualised return from rolling
+// down a calendar spread with the curve unchanged
+func CalendarRollYield(near, far, T1, T2 float64) float64 {
+	return math.Log(near/far) / (T2 - T1)
+}
+
+// FuturesContract is one contract's price series, front month first. Times are
+// increasing observation times (e.g. day numbers) shared across contracts. Roll
+// is the last time to hold the contract and defaults to its final observation.
+type FuturesContract struct {
+	Symbol string    `json:"symbol"`
+	Times  []float64 `json:"times"`
+	Prices []float64 `json:"prices"`
+	Roll   float64   `json:"roll"`
+}
+
+type FuturesRoll struct {
+	Time  float64 `json:"time"`
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+	Gap   float64 `json:"gap"`   // New contract less old at the roll
+	Ratio float64 `json:"ratio"` // New contract over old at the roll
+}
+
+type ContinuousSeries struct {
+	Times         []float64     `json:"times"`
+	Contracts     []string      `json:"contracts"` // Contract held at each time
+	Raw           []float64     `json:"raw"`
+	BackAdjusted  []float64     `json:"back_adjusted"`
+	RatioAdjusted []float64     `json:"ratio_adjusted"`
+	Rolls         []FuturesRoll `json:"rolls"`
+}
+
+// ContinuousFutures stitches contracts into one series, rolling to the next
+// contract at the latest common observation on or before each roll time. Earlier
+// segments are shifted by later roll gaps (back-adjusted) or scaled by later roll
+// ratios (ratio-adjusted), so the current contract's prices are left unchanged.
+func ContinuousFutures(contracts []FuturesContract) (ContinuousSeries, error) {
+	if len(contracts) == 0 {
+		return ContinuousSeries{}, errors.New("at least one contract is required")
+	}
+	for _, k := range contracts {
+		if len(k.Times) == 0 || len(k.Times) != len(k.Prices) {
+			return ContinuousSeries{}, errors.New("each contract needs matching, non-empty times and prices")
+		}
+		for i, t := range k.Times {
+			if i > 0 && t <= k.Times[i-1] {
+				return ContinuousSeries{}, errors.New("contract times must be increasing")
+			}
+			if k.Prices[i] <= 0 {
+				return ContinuousSeries{}, errors.New("contract prices must be positive")
+			}
+		}
+	}
+
+	series := ContinuousSeries{Rolls: []FuturesRoll{}}
+	var segments []int // Index in the series where each contract starts
+	start := math.Inf(-1)
+	for i, k := range contracts {
+		end := math.Inf(1)
+		if i < len(contracts)-1 {
+			next := contracts[i+1]
+			limit := k.Roll
+			if limit == 0 {
+				limit = k.Times[len(k.Times)-1]
+			}
+			roll := -1
+			nextPrices := make(map[float64]float64, len(next.Times))
+			for j, t := range next.Times {
+				nextPrices[t] = next.Prices[j]
+			}
+			for j, t := range k.Times {
+				if _, ok := nextPrices[t]; ok && t > start && t <= limit {
+					roll = j
+				}
+			}
+			if roll < 0 {
+				return ContinuousSeries{}, errors.New("no common observation to roll from " + k.Symbol + " to " + next.Symbol)
+			}
+			end = k.Times[roll]
+			oldPrice, newPrice := k.Prices[roll], nextPrices[end]
+			series.Rolls = append(series.Rolls, FuturesRoll{Time: end, From: k.Symbol, To: next.Symbol, Gap: newPrice - oldPrice, Ratio: newPrice / oldPrice})
+		}
+
+		segments = append(segments, len(series.Times))
+		for j, t := range k.Times {
+			if t > start && t <= end {
+				series.Times = append(series.Times, t)
+				series.Contracts = append(series.Contracts, k.Symbol)
+				series.Raw = append(series.Raw, k.Prices[j])
+			}
+		}
+		start = end
+	}
+
+	n := len(series.Raw)
+	series.BackAdjusted = make([]float64, n)
+	series.RatioAdjusted = make([]float64, n)
+	shift, scale := 0.0, 1.0
+	for s := len(segments) - 1; s >= 0; s-- {
+		if s < len(series.Rolls) {
+			shift += series.Rolls[s].Gap
+			scale *= series.Rolls[s].Ratio
+		}
+		end := n
+		if s+1 < len(segments) {
+			end = segments[s+1]
+		}
+		for i := segments[s]; i < end; i++ {
+			series.BackAdjusted[i] = series.Raw[i] + shift
+			series.RatioAdjusted[i] = series.Raw[i] * scale
+		}
+	}
+	return series, nil
+}
@@ -0,0 +1,247 @@
+package valuation
+
+import (
+	"errors"
+	"math"
+)
+
+// Forecast drives free cash flow year by year. Margins and ratios are fractions
+// of that year's revenue; a ratio given once applies to every year. Working
+// capital changes are measured from BaseRevenue, the last actual year, which
+// defaults to the first forecast year.
+type Forecast struct {
+	Revenue        []float64 `json:"revenue"`
+	BaseRevenue    float64   `json:"base_revenue"`
+	EBITMargin     []float64 `json:"ebit_margin"`
+	TaxRate        float64   `json:"tax_rate"`
+	Depreciation   []float64 `json:"depreciation"`
+	Capex          []float64 `json:"capex"`
+	WorkingCapital []float64 `json:"working_capital"`
+}
+
+// CAPM builds a WACC from the cost of equity rf + β·MRP and an after-tax cost
+// of debt, weighted by DebtWeight = D/(D+E)
+type CAPM struct {
+	RiskFree      float64 `json:"risk_free_rate"`
+	Beta          float64 `json:"beta"`
+	MarketPremium float64 `json:"market_premium"`
+	CostOfDebt    float64 `json:"cost_of_debt"`
+	DebtWeight    float64 `json:"debt_weight"`
+}
+
+func (c CAPM) CostOfEquity() float64 { return c.RiskFree + c.Beta*c.MarketPremium }
+
+func (c CAPM) WACC(taxRate float64) (float64, error) {
+	if c.DebtWeight < 0 || c.DebtWeight >= 1 {
+		return 0, errors.New("debt weight must be in [0, 1)")
+	}
+	return (1-c.DebtWeight)*c.CostOfEquity() + c.DebtWeight*c.CostOfDebt*(1-taxRate), nil
+}
+
+// DCF values the forecast with a Gordon growth terminal value, or an exit
+// multiple of final-year EBITDA when ExitMultiple is set
+type DCF struct {
+	Forecast
+	WACC         float64 `json:"wacc"`
+	Growth       float64 `json:"terminal_growth"`
+	ExitMultiple float64 `json:"exit_multiple"`
+	MidYear      bool    `json:"mid_year"`
+	NetDebt      float64 `json:"net_debt"`
+	Shares       float64 `json:"shares"`
+}
+
+type YearValue struct {
+	Year           int     `json:"year"`
+	Revenue        float64 `json:"revenue"`
+	EBIT           float64 `json:"ebit"`
+	NOPAT          float64 `json:"nopat"`
+	Depreciation   float64 `json:"depreciation"`
+	Capex          float64 `json:"capex"`
+	ChangeInNWC    float64 `json:"change_in_nwc"`
+	FreeCashFlow   float64 `json:"free_cash_flow"`
+	DiscountFactor float64 `json:"discount_factor"`
+	PresentValue   float64 `json:"present_value"`
+}
+
+type DCFResult struct {
+	Years           []YearValue `json:"years"`
+	PVCashFlows     float64     `json:"pv_cash_flows"`
+	TerminalValue   float64     `json:"terminal_value"`
+	PVTerminal      float64     `json:"pv_terminal"`
+	EnterpriseValue float64     `json:"enterprise_value"`
+	EquityValue     float64     `json:"equity_value"`
+	PerShare        *float64    `json:"per_share"`
+	TerminalShare   float64     `json:"terminal_share"`   // PV of terminal value over enterprise value
+	ImpliedMultiple float64     `json:"implied_multiple"` // Terminal value over final EBITDA
+	ImpliedGrowth   float64     `json:"implied_growth"`   // Perpetual growth consistent with the terminal value
+}
+
+func at(values []float64, i int) float64 {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values[i]
+}
+
+func (f Forecast) validate() error {
+	n := len(f.Revenue)
+	if n == 0 || n > 100 {
+		return errors.New("revenue needs between 1 and 100 forecast years")
+	}
+	for _, ratios := range [][]float64{f.EBITMargin, f.Depreciation, f.Capex, f.WorkingCapital} {
+		if len(ratios) > 1 && len(ratios) != n {
+			return errors.New("margins and ratios need one value or one per forecast year")
+		}
+	}
+	if len(f.EBITMargin) == 0 {
+		return errors.New("ebit_margin is required")
+	}
+	if f.TaxRate < 0 || f.TaxRate >= 1 {
+		return errors.New("tax rate must be in [0, 1)")
+	}
+	return nil
+}
+
+// Project computes free cash flow, EBIT(1 - t) + D&A - capex - ΔNWC, for each year
+func (f Forecast) Project() ([]YearValue, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	ratio := func(values []float64, i int) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+		return at(values, i)
+	}
+	prevRevenue := f.BaseRevenue
+	if prevRevenue == 0 {
+		prevRevenue = f.Revenue[0]
+	}
+
+	years := make([]YearValue, len(f.Revenue))
+	for i, revenue := range f.Revenue {
+		y := YearValue{Year: i + 1, Revenue: revenue}
+		y.EBIT = revenue * at(f.EBITMargin, i)
+		y.NOPAT = y.EBIT * (1 - f.TaxRate)
+		y.Depreciation = revenue * ratio(f.Depreciation, i)
+		y.Capex = revenue * ratio(f.Capex, i)
+		nwc := ratio(f.WorkingCapital, i)
+		prevNWC := prevRevenue * nwc
+		if i > 0 {
+			prevNWC = prevRevenue * ratio(f.WorkingCapital, i-1)
+		}
+		y.ChangeInNWC = revenue*nwc - prevNWC
+		y.FreeCashFlow = y.NOPAT + y.Depreciation - y.Capex - y.ChangeInNWC
+		years[i] = y
+		prevRevenue = revenue
+	}
+	return years, nil
+}
+
+// Value discounts the projected cash flows and terminal value at WACC. Mid-year
+// discounting shifts the cash flows, but not the terminal value, half a year in.
+func (d DCF) Value() (DCFResult, error) {
+	years, err := d.Project()
+	if err != nil {
+		return DCFResult{}, err
+	}
+	if d.WACC <= -1 {
+		return DCFResult{}, errors.New("WACC must be above -100%")
+	}
+	if d.ExitMultiple < 0 {
+		return DCFResult{}, errors.New("exit multiple cannot be negative")
+	}
+	if d.ExitMultiple == 0 && d.WACC <= d.Growth {
+		return DCFResult{}, errors.New("WACC must exceed terminal growth")
+	}
+
+	result := DCFResult{Years: years}
+	for i := range years {
+		t := float64(i + 1)
+		if d.MidYear {
+			t -= 0.5
+		}
+		years[i].DiscountFactor = math.Pow(1+d.WACC, -t)
+		years[i].PresentValue = years[i].FreeCashFlow * years[i].DiscountFactor
+		result.PVCashFlows += years[i].PresentValue
+	}
+
+	last := years[len(years)-1]
+	ebitda := last.EBIT + last.Depreciation
+	if d.ExitMultiple > 0 {
+		result.TerminalValue = d.ExitMultiple * ebitda
+		// TV = FCF(1 + g)/(WACC - g) solved for g
+		result.ImpliedGrowth = (result.TerminalValue*d.WACC - last.FreeCashFlow) / (result.TerminalValue + last.FreeCashFlow)
+	} else {
+		result.TerminalValue = last.FreeCashFlow * (1 + d.Growth) / (d.WACC - d.Growth)
+		result.ImpliedGrowth = d.Growth
+	}
+	if ebitda != 0 {
+		result.ImpliedMultiple = result.TerminalValue / ebitda
+	}
+	result.PVTerminal = result.TerminalValue * math.Pow(1+d.WACC, -float64(len(years)))
+
+	result.EnterpriseValue = result.PVCashFlows + result.PVTerminal
+	result.EquityValue = result.EnterpriseValue - d.NetDebt
+	if d.Shares > 0 {
+		perShare := result.EquityValue / d.Shares
+		result.PerShare = &perShare
+	}
+	if result.EnterpriseValue != 0 {
+		result.TerminalShare = result.PVTerminal / result.EnterpriseValue
+	}
+	return result, nil
+}
+
+// Sensitivity holds per-share value, or equity value without a share count, for
+// each WACC (rows) and terminal growth or exit multiple (columns). Cells where
+// WACC does not exceed growth are nil.
+type Sensitivity struct {
+	WACC     []float64    `json:"wacc"`
+	Terminal []float64    `json:"terminal"`
+	Axis     string       `json:"axis"` // terminal_growth or exit_multiple
+	Values   [][]*float64 `json:"values"`
+}
+
+// Sensitivity revalues on a (2n+1)² grid centred on the base case, stepping WACC
+// by waccStep and growth or the exit multiple by terminalStep
+func (d DCF) Sensitivity(n int, waccStep, terminalStep float64) (Sensitivity, error) {
+	if n < 0 || n > 10 || waccStep <= 0 || terminalStep <= 0 {
+		return Sensitivity{}, errors.New("sensitivity needs 0 to 10 steps each way and positive step sizes")
+	}
+	s := Sensitivity{Axis: "terminal_growth"}
+	base := d.Growth
+	if d.ExitMultiple > 0 {
+		s.Axis, base = "exit_multiple", d.ExitMultiple
+	}
+	for i := -n; i <= n; i++ {
+		s.WACC = append(s.WACC, d.WACC+float64(i)*waccStep)
+		if t := base + float64(i)*terminalStep; d.ExitMultiple == 0 || t > 0 {
+			s.Terminal = append(s.Terminal, t)
+		}
+	}
+
+	s.Values = make([][]*float64, len(s.WACC))
+	for i, wacc := range s.WACC {
+		s.Values[i] = make([]*float64, len(s.Terminal))
+		for j, t := range s.Terminal {
+			scenario := d
+			scenario.WACC = wacc
+			if d.ExitMultiple > 0 {
+				scenario.ExitMultiple = t
+			} else {
+				scenario.Growth = t
+			}
+			r, err := scenario.Value()
+			if err != nil {
+				continue
+			}
+			v := r.EquityValue
+			if r.PerShare != nil {
+				v = *r.PerShare
+			}
+			s.Values[i][j] = &v
+		}
+	}
+	return s, nil
+}
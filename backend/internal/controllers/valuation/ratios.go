@@ -0,0 +1,282 @@
+package valuation
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Statement fields are pointers so a missing line item is told apart from a
+// reported zero. Capex and dividends are positive outflows.
+type IncomeStatement struct {
+	Revenue         *float64 `json:"revenue"`
+	CostOfRevenue   *float64 `json:"cost_of_revenue"`
+	GrossProfit     *float64 `json:"gross_profit"`
+	OperatingIncome *float64 `json:"operating_income"`
+	SGA             *float64 `json:"sga"`
+	Depreciation    *float64 `json:"depreciation"`
+	EBITDA          *float64 `json:"ebitda"`
+	InterestExpense *float64 `json:"interest_expense"`
+	PretaxIncome    *float64 `json:"pretax_income"`
+	IncomeTax       *float64 `json:"income_tax"`
+	NetIncome       *float64 `json:"net_income"`
+}
+
+type BalanceSheet struct {
+	TotalAssets          *float64 `json:"total_assets"`
+	CurrentAssets        *float64 `json:"current_assets"`
+	Cash                 *float64 `json:"cash"`
+	ShortTermInvestments *float64 `json:"short_term_investments"`
+	Receivables          *float64 `json:"receivables"`
+	Inventory            *float64 `json:"inventory"`
+	NetPPE               *float64 `json:"net_ppe"`
+	LongTermInvestments  *float64 `json:"long_term_investments"`
+	TotalLiabilities     *float64 `json:"total_liabilities"`
+	CurrentLiabilities   *float64 `json:"current_liabilities"`
+	ShortTermDebt        *float64 `json:"short_term_debt"`
+	LongTermDebt         *float64 `json:"long_term_debt"`
+	TotalEquity          *float64 `json:"total_equity"`
+	RetainedEarnings     *float64 `json:"retained_earnings"`
+}
+
+type CashFlowStatement struct {
+	OperatingCashFlow *float64 `json:"operating_cash_flow"`
+	Capex             *float64 `json:"capex"`
+	DividendsPaid     *float64 `json:"dividends_paid"`
+}
+
+type MarketData struct {
+	Price  *float64 `json:"price"`
+	Shares *float64 `json:"shares_outstanding"`
+}
+
+// Period is one fiscal period's statements; periods are given oldest first
+type Period struct {
+	Label    string            `json:"label"`
+	Income   IncomeStatement   `json:"income_statement"`
+	Balance  BalanceSheet      `json:"balance_sheet"`
+	CashFlow CashFlowStatement `json:"cash_flow"`
+	Market   MarketData        `json:"market"`
+}
+
+type RatioWarning struct {
+	Ratio   string `json:"ratio"`
+	Message string `json:"message"`
+}
+
+type PeriodRatios struct {
+	Label    string                         `json:"label"`
+	Ratios   map[string]map[string]*float64 `json:"ratios"` // By category, nil where not computable
+	DuPont   map[string]*float64            `json:"dupont"`
+	Growth   map[string]*float64            `json:"growth"` // Nil for the first period
+	Warnings []RatioWarning                 `json:"warnings"`
+}
+
+// fields flattens the statements and fills derived items from their parts when
+// not reported directly
+func (p Period) fields() map[string]*float64 {
+	f := map[string]*float64{
+		"revenue":                p.Income.Revenue,
+		"cost_of_revenue":        p.Income.CostOfRevenue,
+		"gross_profit":           p.Income.GrossProfit,
+		"operating_income":       p.Income.OperatingIncome,
+		"sga":                    p.Income.SGA,
+		"depreciation":           p.Income.Depreciation,
+		"ebitda":                 p.Income.EBITDA,
+		"interest_expense":       p.Income.InterestExpense,
+		"pretax_income":          p.Income.PretaxIncome,
+		"income_tax":             p.Income.IncomeTax,
+		"net_income":             p.Income.NetIncome,
+		"total_assets":           p.Balance.TotalAssets,
+		"current_assets":         p.Balance.CurrentAssets,
+		"cash":                   p.Balance.Cash,
+		"short_term_investments": p.Balance.ShortTermInvestments,
+		"receivables":            p.Balance.Receivables,
+		"inventory":              p.Balance.Inventory,
+		"net_ppe":                p.Balance.NetPPE,
+		"long_term_investments":  p.Balance.LongTermInvestments,
+		"total_liabilities":      p.Balance.TotalLiabilities,
+		"current_liabilities":    p.Balance.CurrentLiabilities,
+		"short_term_debt":        p.Balance.ShortTermDebt,
+		"long_term_debt":         p.Balance.LongTermDebt,
+		"total_equity":           p.Balance.TotalEquity,
+		"retained_earnings":      p.Balance.RetainedEarnings,
+		"operating_cash_flow":    p.CashFlow.OperatingCashFlow,
+		"capex":                  p.CashFlow.Capex,
+		"dividends_paid":         p.CashFlow.DividendsPaid,
+		"price":                  p.Market.Price,
+		"shares_outstanding":     p.Market.Shares,
+	}
+	derive := func(name string, fn func(v ...float64) float64, inputs ...string) {
+		if f[name] != nil {
+			return
+		}
+		values := make([]float64, len(inputs))
+		for i, in := range inputs {
+			if f[in] == nil {
+				return
+			}
+			values[i] = *f[in]
+		}
+		v := fn(values...)
+		f[name] = &v
+	}
+	sum := func(v ...float64) float64 { return v[0] + v[1] }
+	diff := func(v ...float64) float64 { return v[0] - v[1] }
+	product := func(v ...float64) float64 { return v[0] * v[1] }
+
+	derive("gross_profit", diff, "revenue", "cost_of_revenue")
+	derive("cost_of_revenue", diff, "revenue", "gross_profit")
+	derive("ebitda", sum, "operating_income", "depreciation")
+	derive("total_debt", sum, "short_term_debt", "long_term_debt")
+	derive("working_capital", diff, "current_assets", "current_liabilities")
+	derive("free_cash_flow", diff, "operating_cash_flow", "capex")
+	derive("market_cap", product, "price", "shares_outstanding")
+	derive("net_debt", diff, "total_debt", "cash")
+	derive("enterprise_value", sum, "market_cap", "net_debt")
+	derive("tax_rate", func(v ...float64) float64 { return v[0] / v[1] }, "income_tax", "pretax_income")
+	derive("eps", func(v ...float64) float64 { return v[0] / v[1] }, "net_income", "shares_outstanding")
+	return f
+}
+
+type ratioSpec struct {
+	category, name string
+	inputs         []string
+	fn             func(v []float64) float64
+}
+
+func ratio(category, name, numerator, denominator string) ratioSpec {
+	return ratioSpec{category, name, []string{numerator, denominator}, func(v []float64) float64 { return v[0] / v[1] }}
+}
+
+// Balance sheet items are period-end values rather than averages
+var ratioSpecs = []ratioSpec{
+	ratio("profitability", "gross_margin", "gross_profit", "revenue"),
+	ratio("profitability", "operating_margin", "operating_income", "revenue"),
+	ratio("profitability", "ebitda_margin", "ebitda", "revenue"),
+	ratio("profitability", "net_margin", "net_income", "revenue"),
+	ratio("profitability", "roa", "net_income", "total_assets"),
+	ratio("profitability", "roe", "net_income", "total_equity"),
+	{"profitability", "roic", []string{"operating_income", "tax_rate", "total_debt", "total_equity", "cash"}, func(v []float64) float64 {
+		return v[0] * (1 - v[1]) / (v[2] + v[3] - v[4])
+	}},
+
+	ratio("liquidity", "current_ratio", "current_assets", "current_liabilities"),
+	{"liquidity", "quick_ratio", []string{"current_assets", "inventory", "current_liabilities"}, func(v []float64) float64 {
+		return (v[0] - v[1]) / v[2]
+	}},
+	ratio("liquidity", "cash_ratio", "cash", "current_liabilities"),
+
+	ratio("leverage", "debt_to_equity", "total_debt", "total_equity"),
+	ratio("leverage", "debt_to_assets", "total_debt", "total_assets"),
+	ratio("leverage", "liabilities_to_assets", "total_liabilities", "total_assets"),
+	ratio("leverage", "equity_multiplier", "total_assets", "total_equity"),
+	ratio("leverage", "interest_coverage", "operating_income", "interest_expense"),
+	ratio("leverage", "net_debt_to_ebitda", "net_debt", "ebitda"),
+
+	ratio("efficiency", "asset_turnover", "revenue", "total_assets"),
+	ratio("efficiency", "inventory_turnover", "cost_of_revenue", "inventory"),
+	ratio("efficiency", "receivables_turnover", "revenue", "receivables"),
+	{"efficiency", "days_sales_outstanding", []string{"receivables", "revenue"}, func(v []float64) float64 { return 365 * v[0] / v[1] }},
+	{"efficiency", "days_inventory", []string{"inventory", "cost_of_revenue"}, func(v []float64) float64 { return 365 * v[0] / v[1] }},
+
+	ratio("valuation", "pe", "price", "eps"),
+	ratio("valuation", "pb", "market_cap", "total_equity"),
+	ratio("valuation", "ps", "market_cap", "revenue"),
+	ratio("valuation", "ev_ebitda", "enterprise_value", "ebitda"),
+	ratio("valuation", "ev_sales", "enterprise_value", "revenue"),
+	ratio("valuation", "earnings_yield", "net_income", "market_cap"),
+	ratio("valuation", "fcf_yield", "free_cash_flow", "market_cap"),
+	ratio("valuation", "dividend_yield", "dividends_paid", "market_cap"),
+}
+
+// DuPont splits ROE into tax burden × interest burden × operating margin ×
+// asset turnover × equity multiplier; the first three multiply to net margin
+var dupontSpecs = []ratioSpec{
+	ratio("dupont", "tax_burden", "net_income", "pretax_income"),
+	ratio("dupont", "interest_burden", "pretax_income", "operating_income"),
+	ratio("dupont", "operating_margin", "operating_income", "revenue"),
+	ratio("dupont", "net_margin", "net_income", "revenue"),
+	ratio("dupont", "asset_turnover", "revenue", "total_assets"),
+	ratio("dupont", "equity_multiplier", "total_assets", "total_equity"),
+	ratio("dupont", "roe", "net_income", "total_equity"),
+}
+
+var growthFields = []string{"revenue", "gross_profit", "operating_income", "ebitda", "net_income", "eps", "free_cash_flow", "total_assets", "total_equity"}
+
+// evaluate returns nil with a warning naming the missing inputs, or the zero
+// denominator, when the ratio cannot be computed
+func (s ratioSpec) evaluate(f map[string]*float64, warnings *[]RatioWarning) *float64 {
+	values := make([]float64, len(s.inputs))
+	var missing []string
+	for i, in := range s.inputs {
+		if f[in] == nil {
+			missing = append(missing, in)
+			continue
+		}
+		values[i] = *f[in]
+	}
+	if len(missing) > 0 {
+		*warnings = append(*warnings, RatioWarning{Ratio: s.name, Message: "missing " + strings.Join(missing, ", ")})
+		return nil
+	}
+	v := s.fn(values)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		*warnings = append(*warnings, RatioWarning{Ratio: s.name, Message: "zero denominator"})
+		return nil
+	}
+	return &v
+}
+
+// growth is the change over |previous| so a shrinking loss reads as growth
+func growth(prev, cur *float64) (*float64, string) {
+	switch {
+	case prev == nil || cur == nil:
+		return nil, "missing in this or the previous period"
+	case *prev == 0:
+		return nil, "previous period is zero"
+	}
+	g := (*cur - *prev) / math.Abs(*prev)
+	return &g, ""
+}
+
+// Ratios computes every ratio for each period, and growth against the period
+// before it
+func Ratios(periods []Period) ([]PeriodRatios, error) {
+	if len(periods) == 0 {
+		return nil, errors.New("at least one period is required")
+	}
+	results := make([]PeriodRatios, len(periods))
+	var prev map[string]*float64
+	for i, p := range periods {
+		f := p.fields()
+		r := PeriodRatios{Label: p.Label, Ratios: map[string]map[string]*float64{}, DuPont: map[string]*float64{}, Warnings: []RatioWarning{}}
+		for _, s := range ratioSpecs {
+			if r.Ratios[s.category] == nil {
+				r.Ratios[s.category] = map[string]*float64{}
+			}
+			r.Ratios[s.category][s.name] = s.evaluate(f, &r.Warnings)
+		}
+		var dupontWarnings []RatioWarning
+		for _, s := range dupontSpecs {
+			r.DuPont[s.name] = s.evaluate(f, &dupontWarnings)
+		}
+		for _, w := range dupontWarnings {
+			w.Ratio = "dupont." + w.Ratio
+			r.Warnings = append(r.Warnings, w)
+		}
+		if prev != nil {
+			r.Growth = map[string]*float64{}
+			for _, name := range growthFields {
+				g, msg := growth(prev[name], f[name])
+				r.Growth[name] = g
+				if g == nil {
+					r.Warnings = append(r.Warnings, RatioWarning{Ratio: "growth." + name, Message: msg})
+				}
+			}
+		}
+		results[i] = r
+		prev = f
+	}
+	return results, nil
+}
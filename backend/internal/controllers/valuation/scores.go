@@ -0,0 +1,345 @@
+package valuation
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// lookup returns the named fields, or the names of those missing
+func lookup(f map[string]*float64, names ...string) ([]float64, []string) {
+	values := make([]float64, len(names))
+	var missing []string
+	for i, name := range names {
+		if f[name] == nil {
+			missing = append(missing, name)
+			continue
+		}
+		values[i] = *f[name]
+	}
+	return values, missing
+}
+
+func missingWarning(score string, missing []string) RatioWarning {
+	return RatioWarning{Ratio: score, Message: "missing " + strings.Join(missing, ", ")}
+}
+
+func ratioOf(f map[string]*float64, numerator, denominator string) *float64 {
+	v, missing := lookup(f, numerator, denominator)
+	if len(missing) > 0 || v[1] == 0 {
+		return nil
+	}
+	r := v[0] / v[1]
+	return &r
+}
+
+type Piotroski struct {
+	Score          int             `json:"score"`
+	Available      int             `json:"available"` // Signals with the data to evaluate, out of 9
+	Components     map[string]*int `json:"components"`
+	Interpretation string          `json:"interpretation"`
+}
+
+// piotroski scores nine binary signals of profitability, funding and operating
+// efficiency against the prior period, using period-end balances throughout
+func piotroski(cur, prev map[string]*float64) (Piotroski, []string) {
+	p := Piotroski{Components: map[string]*int{}}
+	var unavailable []string
+	signal := func(name string, ok bool, pass bool) {
+		if !ok {
+			p.Components[name] = nil
+			unavailable = append(unavailable, name)
+			return
+		}
+		v := 0
+		if pass {
+			v = 1
+		}
+		p.Components[name] = &v
+		p.Score += v
+		p.Available++
+	}
+	improved := func(name, numerator, denominator string, higher bool) {
+		a, b := ratioOf(cur, numerator, denominator), ratioOf(prev, numerator, denominator)
+		if a == nil || b == nil {
+			signal(name, false, false)
+			return
+		}
+		// A ratio that should fall cannot go below zero, so staying at zero passes
+		floored := !higher && *a == 0 && *b == 0
+		signal(name, true, ((*a > *b) == higher && *a != *b) || floored)
+	}
+
+	roa := ratioOf(cur, "net_income", "total_assets")
+	signal("roa_positive", roa != nil, roa != nil && *roa > 0)
+	cfo := cur["operating_cash_flow"]
+	signal("cfo_positive", cfo != nil, cfo != nil && *cfo > 0)
+	improved("roa_improving", "net_income", "total_assets", true)
+	v, missing := lookup(cur, "operating_cash_flow", "net_income")
+	signal("accruals", len(missing) == 0, len(missing) == 0 && v[0] > v[1])
+	improved("leverage_falling", "long_term_debt", "total_assets", false)
+	improved("liquidity_improving", "current_assets", "current_liabilities", true)
+	shares, sharesPrev := cur["shares_outstanding"], prev["shares_outstanding"]
+	signal("no_dilution", shares != nil && sharesPrev != nil, shares != nil && sharesPrev != nil && *shares <= *sharesPrev)
+	improved("margin_improving", "gross_profit", "revenue", true)
+	improved("turnover_improving", "revenue", "total_assets", true)
+
+	switch {
+	case p.Score >= 8:
+		p.Interpretation = "strong"
+	case p.Score <= 2 && p.Available == 9:
+		p.Interpretation = "weak"
+	default:
+		p.Interpretation = "neutral"
+	}
+	if p.Available < 9 {
+		p.Interpretation += " (incomplete data)"
+	}
+	return p, unavailable
+}
+
+type AltmanModel string
+
+const (
+	AltmanPublic           AltmanModel = "public"            // Original Z for listed manufacturers
+	AltmanPrivate          AltmanModel = "private"           // Z' with book equity
+	AltmanNonManufacturing AltmanModel = "non_manufacturing" // Z'' without the sales term
+)
+
+type Altman struct {
+	Model          AltmanModel        `json:"model"`
+	Score          float64            `json:"score"`
+	Components     map[string]float64 `json:"components"`
+	Interpretation string             `json:"interpretation"` // safe, grey or distress zone
+}
+
+func altman(f map[string]*float64, model AltmanModel) (*Altman, []string) {
+	equity := "market_cap"
+	if model != AltmanPublic {
+		equity = "total_equity"
+	}
+	v, missing := lookup(f, "working_capital", "retained_earnings", "operating_income", equity, "total_liabilities", "revenue", "total_assets")
+	if len(missing) > 0 {
+		return nil, missing
+	}
+	if v[4] == 0 || v[6] == 0 {
+		return nil, []string{"non-zero total_liabilities and total_assets"}
+	}
+	x := map[string]float64{
+		"working_capital_to_assets":   v[0] / v[6],
+		"retained_earnings_to_assets": v[1] / v[6],
+		"ebit_to_assets":              v[2] / v[6],
+		"equity_to_liabilities":       v[3] / v[4],
+		"sales_to_assets":             v[5] / v[6],
+	}
+
+	a := &Altman{Model: model, Components: x}
+	var safe, distress float64
+	switch model {
+	case AltmanPrivate:
+		a.Score = 0.717*x["working_capital_to_assets"] + 0.847*x["retained_earnings_to_assets"] + 3.107*x["ebit_to_assets"] + 0.420*x["equity_to_liabilities"] + 0.998*x["sales_to_assets"]
+		safe, distress = 2.9, 1.23
+	case AltmanNonManufacturing:
+		delete(x, "sales_to_assets")
+		a.Score = 6.56*x["working_capital_to_assets"] + 3.26*x["retained_earnings_to_assets"] + 6.72*x["ebit_to_assets"] + 1.05*x["equity_to_liabilities"]
+		safe, distress = 2.6, 1.1
+	default:
+		a.Score = 1.2*x["working_capital_to_assets"] + 1.4*x["retained_earnings_to_assets"] + 3.3*x["ebit_to_assets"] + 0.6*x["equity_to_liabilities"] + 1.0*x["sales_to_assets"]
+		safe, distress = 2.99, 1.81
+	}
+	switch {
+	case a.Score > safe:
+		a.Interpretation = "safe"
+	case a.Score < distress:
+		a.Interpretation = "distress"
+	default:
+		a.Interpretation = "grey"
+	}
+	return a, nil
+}
+
+type Beneish struct {
+	Score          float64            `json:"score"`
+	Components     map[string]float64 `json:"components"`
+	Interpretation string             `json:"interpretation"`
+}
+
+// beneish is the eight-variable M-score; above -1.78 flags a likely earnings
+// manipulator. Long-term investments count as zero when not reported.
+func beneish(cur, prev map[string]*float64) (*Beneish, []string) {
+	fields := []string{"revenue", "receivables", "gross_profit", "current_assets", "net_ppe", "total_assets",
+		"depreciation", "sga", "current_liabilities", "long_term_debt", "net_income", "operating_cash_flow"}
+	c, missing := lookup(cur, fields...)
+	p, missingPrev := lookup(prev, fields...)
+	for _, m := range missingPrev {
+		missing = append(missing, "prior "+m)
+	}
+	if len(missing) > 0 {
+		return nil, missing
+	}
+	get := func(v []float64, name string) float64 {
+		for i, f := range fields {
+			if f == name {
+				return v[i]
+			}
+		}
+		return 0
+	}
+	investments := func(f map[string]*float64) float64 {
+		if f["long_term_investments"] == nil {
+			return 0
+		}
+		return *f["long_term_investments"]
+	}
+	assetQuality := func(v []float64, f map[string]*float64) float64 {
+		return 1 - (get(v, "current_assets")+get(v, "net_ppe")+investments(f))/get(v, "total_assets")
+	}
+	depRate := func(v []float64) float64 {
+		return get(v, "depreciation") / (get(v, "depreciation") + get(v, "net_ppe"))
+	}
+	leverage := func(v []float64) float64 {
+		return (get(v, "current_liabilities") + get(v, "long_term_debt")) / get(v, "total_assets")
+	}
+	index := func(name string) float64 {
+		return (get(c, name) / get(c, "revenue")) / (get(p, name) / get(p, "revenue"))
+	}
+
+	x := map[string]float64{
+		"dsri": index("receivables"),
+		"gmi":  (get(p, "gross_profit") / get(p, "revenue")) / (get(c, "gross_profit") / get(c, "revenue")),
+		"aqi":  assetQuality(c, cur) / assetQuality(p, prev),
+		"sgi":  get(c, "revenue") / get(p, "revenue"),
+		"depi": depRate(p) / depRate(c),
+		"sgai": index("sga"),
+		"lvgi": leverage(c) / leverage(p),
+		"tata": (get(c, "net_income") - get(c, "operating_cash_flow")) / get(c, "total_assets"),
+	}
+	for _, v := range x {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, []string{"non-zero denominators in this and the prior period"}
+		}
+	}
+	b := &Beneish{Components: x}
+	b.Score = -4.84 + 0.920*x["dsri"] + 0.528*x["gmi"] + 0.404*x["aqi"] + 0.892*x["sgi"] + 0.115*x["depi"] -
+		0.172*x["sgai"] + 4.679*x["tata"] - 0.327*x["lvgi"]
+	b.Interpretation = "unlikely manipulator"
+	if b.Score > -1.78 {
+		b.Interpretation = "likely manipulator"
+	}
+	return b, nil
+}
+
+type Ohlson struct {
+	Score              float64            `json:"score"`
+	DefaultProbability float64            `json:"default_probability"`
+	Components         map[string]float64 `json:"components"`
+	Interpretation     string             `json:"interpretation"`
+}
+
+// ohlson is the O-score with total assets in millions deflated by priceLevel, a
+// GNP price index relative to the model's base year
+func ohlson(cur, prev map[string]*float64, priceLevel float64) (*Ohlson, []string) {
+	v, missing := lookup(cur, "total_assets", "total_liabilities", "working_capital", "current_liabilities", "current_assets", "net_income", "operating_cash_flow")
+	p, missingPrev := lookup(prev, "net_income")
+	if len(missingPrev) > 0 {
+		missing = append(missing, "prior net_income")
+	}
+	if len(missing) > 0 {
+		return nil, missing
+	}
+	ta, tl, wc, cl, ca, ni, ffo, niPrev := v[0], v[1], v[2], v[3], v[4], v[5], v[6], p[0]
+	if ta <= 0 || tl == 0 || ca == 0 {
+		return nil, []string{"positive total_assets and non-zero total_liabilities and current_assets"}
+	}
+
+	x := map[string]float64{
+		"size":                  math.Log(ta / priceLevel),
+		"liabilities_to_assets": tl / ta,
+		"working_capital":       wc / ta,
+		"current_ratio_inverse": cl / ca,
+		"liabilities_exceed":    0,
+		"roa":                   ni / ta,
+		"ffo_to_liabilities":    ffo / tl,
+		"two_year_loss":         0,
+		"income_change":         0,
+	}
+	if tl > ta {
+		x["liabilities_exceed"] = 1
+	}
+	if ni < 0 && niPrev < 0 {
+		x["two_year_loss"] = 1
+	}
+	if d := math.Abs(ni) + math.Abs(niPrev); d > 0 {
+		x["income_change"] = (ni - niPrev) / d
+	}
+
+	o := &Ohlson{Components: x}
+	o.Score = -1.32 - 0.407*x["size"] + 6.03*x["liabilities_to_assets"] - 1.43*x["working_capital"] +
+		0.0757*x["current_ratio_inverse"] - 1.72*x["liabilities_exceed"] - 2.37*x["roa"] -
+		1.83*x["ffo_to_liabilities"] + 0.285*x["two_year_loss"] - 0.521*x["income_change"]
+	o.DefaultProbability = 1 / (1 + math.Exp(-o.Score))
+	o.Interpretation = "low default risk"
+	if o.DefaultProbability > 0.5 {
+		o.Interpretation = "high default risk"
+	}
+	return o, nil
+}
+
+type PeriodScores struct {
+	Label     string         `json:"label"`
+	Piotroski *Piotroski     `json:"piotroski"`
+	Altman    *Altman        `json:"altman"`
+	Beneish   *Beneish       `json:"beneish"`
+	Ohlson    *Ohlson        `json:"ohlson"`
+	Warnings  []RatioWarning `json:"warnings"`
+}
+
+// Scores computes the Altman Z-score for every period and the Piotroski, Beneish
+// and Ohlson scores, which compare against the prior year, from the second period
+func Scores(periods []Period, model AltmanModel, priceLevel float64) ([]PeriodScores, error) {
+	if len(periods) == 0 {
+		return nil, errors.New("at least one period is required")
+	}
+	switch model {
+	case "":
+		model = AltmanPublic
+	case AltmanPublic, AltmanPrivate, AltmanNonManufacturing:
+	default:
+		return nil, errors.New("altman model must be public, private or non_manufacturing")
+	}
+	if priceLevel == 0 {
+		priceLevel = 1
+	}
+	if priceLevel < 0 {
+		return nil, errors.New("price level must be positive")
+	}
+
+	results := make([]PeriodScores, len(periods))
+	var prev map[string]*float64
+	for i, period := range periods {
+		f := period.fields()
+		r := PeriodScores{Label: period.Label, Warnings: []RatioWarning{}}
+		var missing []string
+		if r.Altman, missing = altman(f, model); missing != nil {
+			r.Warnings = append(r.Warnings, missingWarning("altman", missing))
+		}
+		if prev == nil {
+			r.Warnings = append(r.Warnings, RatioWarning{Ratio: "piotroski, beneish, ohlson", Message: "need a prior period"})
+		} else {
+			p, unavailable := piotroski(f, prev)
+			r.Piotroski = &p
+			for _, name := range unavailable {
+				r.Warnings = append(r.Warnings, RatioWarning{Ratio: "piotroski." + name, Message: "missing inputs in this or the prior period"})
+			}
+			if r.Beneish, missing = beneish(f, prev); missing != nil {
+				r.Warnings = append(r.Warnings, missingWarning("beneish", missing))
+			}
+			if r.Ohlson, missing = ohlson(f, prev, priceLevel); missing != nil {
+				r.Warnings = append(r.Warnings, missingWarning("ohlson", missing))
+			}
+		}
+		results[i] = r
+		prev = f
+	}
+	return results, nil
+}
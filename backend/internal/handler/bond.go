@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/bonds"
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/finmath/curve"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *FinMathHandler) BondPrice(c *gin.Context) {
+	var req BondPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bond, settlement, err := parseBond(req.BondRequest)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := bond.PriceFromYield(settlement, req.Yield)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+func (h *FinMathHandler) BondYield(c *gin.Context) {
+	var req BondYieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bond, settlement, err := parseBond(req.BondRequest)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := bond.YieldFromPrice(settlement, req.Price, req.IsClean == nil || *req.IsClean)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+func (h *FinMathHandler) BondZSpread(c *gin.Context) {
+	var req BondZSpreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bond, settlement, err := parseBond(req.BondRequest)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var rates bonds.RateCurve
+	switch {
+	case req.CurveID != "":
+		if rates, err = curve.Load(req.CurveID); err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	case req.ZeroCurve != nil:
+		if err := req.ZeroCurve.Validate(); err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		rates = *req.ZeroCurve
+	default:
+		h.SendError(c, http.StatusBadRequest, "zero_curve or curve_id is required")
+		return
+	}
+
+	isClean := req.IsClean == nil || *req.IsClean
+	spread, err := bond.ZSpread(settlement, req.Price, isClean, rates)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"z_spread":     spread,
+		"z_spread_bps": spread * 1e4,
+	})
+}
+
+// parseBond reads dates as YYYY-MM-DD and fills in a face value of 100 and
+// semi-annual coupons when they are omitted
+func parseBond(req BondRequest) (bonds.Bond, time.Time, error) {
+	settlement, err := parseDate("settlement_date", req.SettlementDate)
+	if err != nil {
+		return bonds.Bond{}, time.Time{}, err
+	}
+	maturity, err := parseDate("maturity_date", req.MaturityDate)
+	if err != nil {
+		return bonds.Bond{}, time.Time{}, err
+	}
+	if err := calendar.CheckSpan(settlement, maturity); err != nil {
+		return bonds.Bond{}, time.Time{}, err
+	}
+
+	dayCount, err := calendar.ParseDayCount(req.DayCount)
+	if err != nil {
+		return bonds.Bond{}, time.Time{}, err
+	}
+	cal, err := calendar.Lookup(req.Calendar)
+	if err != nil {
+		return bonds.Bond{}, time.Time{}, err
+	}
+
+	if req.Face == 0 {
+		req.Face = 100
+	}
+	if req.Frequency == 0 {
+		req.Frequency = 2
+	}
+
+	return bonds.Bond{
+		Maturity:   maturity,
+		CouponRate: req.CouponRate,
+		Frequency:  req.Frequency,
+		Face:       req.Face,
+		DayCount:   dayCount,
+		Calendar:   cal,
+	}, settlement, nil
+}
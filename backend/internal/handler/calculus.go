@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const maxIntegralIntervals = 1_000_000
+
 type CalculusHandler struct {
 	*BaseHandler
 }
@@ -24,8 +26,9 @@ func (h *CalculusHandler) NumericalDerivative(c *gin.Context) {
 		return
 	}
 
-	if !h.validator.IsValidFunction(req.Function) {
-		h.SendError(c, http.StatusBadRequest, "invalid function")
+	f, err := h.validator.ParseFunction(req.Function, "x^2 + 2*x + 1")
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -34,11 +37,15 @@ func (h *CalculusHandler) NumericalDerivative(c *gin.Context) {
 		return
 	}
 
-	result, err := calculus.NumericalDerivative(req.X, req.H)
+	result, err := calculus.NumericalDerivative(f, req.X, req.H)
 	if err != nil {
 		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !h.validator.IsValidFloat(result) {
+		h.SendError(c, http.StatusBadRequest, "function is not finite over the requested range")
+		return
+	}
 
 	h.SendSuccess(c, result)
 }
@@ -50,8 +57,9 @@ func (h *CalculusHandler) NumericalIntegral(c *gin.Context) {
 		return
 	}
 
-	if !h.validator.IsValidFunction(req.Function) {
-		h.SendError(c, http.StatusBadRequest, "invalid function")
+	f, err := h.validator.ParseFunction(req.Function, "x^2")
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -60,16 +68,20 @@ func (h *CalculusHandler) NumericalIntegral(c *gin.Context) {
 		return
 	}
 
-	if req.N <= 0 {
-		h.SendError(c, http.StatusBadRequest, "intervals must be positive")
+	if req.N <= 0 || req.N > maxIntegralIntervals {
+		h.SendError(c, http.StatusBadRequest, "intervals must be between 1 and 1,000,000")
 		return
 	}
 
-	result, err := calculus.TrapezoidalRule(req.Lower, req.Upper, req.N)
+	result, err := calculus.TrapezoidalRule(f, req.Lower, req.Upper, req.N)
 	if err != nil {
 		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !h.validator.IsValidFloat(result) {
+		h.SendError(c, http.StatusBadRequest, "function is not finite over the requested range")
+		return
+	}
 
 	h.SendSuccess(c, result)
 }
@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// Roughly 40 years of business days
+	maxBusinessDayShift = 10_000
+)
+
+func parseDate(field, value string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New(field + " must be formatted as YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+// resolve returns t unchanged unless an expiry date is given, in which case the
+// time to expiry is measured from the valuation date
+func (d ExpiryDates) resolve(t float64) (float64, error) {
+	if d.ExpiryDate == "" {
+		return t, nil
+	}
+
+	expiry, err := parseDate("expiry_date", d.ExpiryDate)
+	if err != nil {
+		return 0, err
+	}
+	valuation := calendar.Date(time.Now())
+	if d.ValuationDate != "" {
+		if valuation, err = parseDate("valuation_date", d.ValuationDate); err != nil {
+			return 0, err
+		}
+	}
+
+	dayCount := calendar.Act365Fixed
+	if d.DayCount != "" {
+		if dayCount, err = calendar.ParseDayCount(d.DayCount); err != nil {
+			return 0, err
+		}
+	}
+	if err := calendar.CheckSpan(valuation, expiry); err != nil {
+		return 0, err
+	}
+	cal, err := calendar.Lookup(d.Calendar)
+	if err != nil {
+		return 0, err
+	}
+
+	return dayCount.YearFraction(valuation, expiry, cal), nil
+}
+
+func (h *FinMathHandler) YearFraction(c *gin.Context) {
+	var req YearFractionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start, err := parseDate("start_date", req.StartDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	end, err := parseDate("end_date", req.EndDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := calendar.CheckSpan(start, end); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	dayCount, err := calendar.ParseDayCount(req.DayCount)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	cal, err := calendar.Lookup(req.Calendar)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"day_count":     dayCount,
+		"year_fraction": dayCount.YearFraction(start, end, cal),
+		"business_days": cal.BusinessDaysBetween(start, end),
+	})
+}
+
+func (h *FinMathHandler) AdjustDate(c *gin.Context) {
+	var req BusinessDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	date, err := parseDate("date", req.Date)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	convention, err := calendar.ParseConvention(req.Convention)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	cal, err := calendar.Lookup(req.Calendar)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.BusinessDays > maxBusinessDayShift || req.BusinessDays < -maxBusinessDayShift {
+		h.SendError(c, http.StatusBadRequest, "business_days must be between -10,000 and 10,000")
+		return
+	}
+
+	adjusted, err := cal.Adjust(date, convention)
+	if err == nil && req.BusinessDays != 0 {
+		adjusted, err = cal.AddBusinessDays(adjusted, req.BusinessDays)
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"is_business_day": cal.IsBusinessDay(date),
+		"adjusted_date":   adjusted.Format(dateLayout),
+	})
+}
+
+func (h *FinMathHandler) Holidays(c *gin.Context) {
+	var req HolidaysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start, err := parseDate("start_date", req.StartDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	end, err := parseDate("end_date", req.EndDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if end.Sub(start) > 50*366*24*time.Hour {
+		h.SendError(c, http.StatusBadRequest, "date range cannot exceed 50 years")
+		return
+	}
+	if req.Calendar == "" {
+		h.SendError(c, http.StatusBadRequest, "calendar is required")
+		return
+	}
+	cal, err := calendar.Lookup(req.Calendar)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	holidays := []string{}
+	for _, d := range cal.Holidays(start, end) {
+		holidays = append(holidays, d.Format(dateLayout))
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"calendar": cal.Name,
+		"holidays": holidays,
+	})
+}
+
+func (h *FinMathHandler) Schedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start, err := parseDate("start_date", req.StartDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	end, err := parseDate("end_date", req.EndDate)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if end.Sub(start) > 100*366*24*time.Hour {
+		h.SendError(c, http.StatusBadRequest, "schedules cannot exceed 100 years")
+		return
+	}
+	stub, err := calendar.ParseStub(req.Stub)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	convention, err := calendar.ParseConvention(req.Convention)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	cal, err := calendar.Lookup(req.Calendar)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	periods, err := calendar.GenerateSchedule(start, end, req.Frequency, stub, convention, cal, req.EndOfMonth)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedule := make([]gin.H, len(periods))
+	for i, p := range periods {
+		schedule[i] = gin.H{
+			"start":          p.Start.Format(dateLayout),
+			"end":            p.End.Format(dateLayout),
+			"adjusted_start": p.AdjustedStart.Format(dateLayout),
+			"adjusted_end":   p.AdjustedEnd.Format(dateLayout),
+			"is_stub":        p.IsStub,
+		}
+	}
+
+	h.SendSuccess(c, schedule)
+}
+
+// LoadCalendar registers a calendar for every client, so it needs the bearer
+// token set in CALENDAR_LOAD_TOKEN
+func (h *FinMathHandler) LoadCalendar(c *gin.Context) {
+	if h.calendarToken == "" {
+		h.SendError(c, http.StatusForbidden, "calendar uploads are disabled; set CALENDAR_LOAD_TOKEN or use CALENDAR_DIR")
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.calendarToken)) != 1 {
+		h.SendError(c, http.StatusUnauthorized, "a valid calendar upload token is required")
+		return
+	}
+
+	var req LoadCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var cal *calendar.Calendar
+	var err error
+	switch strings.ToLower(req.Format) {
+	case "json":
+		cal, err = calendar.ParseJSON([]byte(req.Data))
+	case "ics":
+		cal, err = calendar.ParseICS(req.Name, []byte(req.Data))
+	default:
+		h.SendError(c, http.StatusBadRequest, "format must be json or ics")
+		return
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := calendar.Register(cal); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	names := calendar.Names()
+	sort.Strings(names)
+
+	h.SendSuccessWithFields(c, gin.H{
+		"calendar":  cal.Name,
+		"calendars": names,
+	})
+}
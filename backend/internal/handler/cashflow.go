@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/cashflow"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxCashflows = 10_000
+
+func (h *FinMathHandler) Cashflow(c *gin.Context) {
+	var req CashflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Amounts) == 0 && req.Annuity == nil {
+		h.SendError(c, http.StatusBadRequest, "amounts or annuity is required")
+		return
+	}
+	if len(req.Amounts) > maxCashflows {
+		h.SendError(c, http.StatusBadRequest, "at most 10,000 cash flows are allowed")
+		return
+	}
+	if req.Frequency == 0 {
+		req.Frequency = 1
+	}
+	if req.Frequency < 0 || req.Frequency > 365 {
+		h.SendError(c, http.StatusBadRequest, "frequency must be between 1 and 365")
+		return
+	}
+
+	flows := cashflow.Periodic(req.Amounts)
+	yearsPerUnit := 1 / float64(req.Frequency)
+	if len(req.Dates) > 0 {
+		dates := make([]time.Time, len(req.Dates))
+		for i, s := range req.Dates {
+			d, err := parseDate("dates", s)
+			if err != nil {
+				h.SendError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			dates[i] = d
+		}
+		var err error
+		if flows, err = cashflow.Dated(dates, req.Amounts); err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		yearsPerUnit = 1
+	}
+
+	response := gin.H{}
+	if req.Annuity != nil {
+		annuity, err := req.Annuity.Value()
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["annuity"] = annuity
+	}
+	if len(flows) == 0 {
+		h.SendSuccessWithFields(c, response)
+		return
+	}
+	response["flows"] = flows
+
+	if req.Rate != nil {
+		npv, err := cashflow.NPV(flows, *req.Rate)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["npv"] = npv
+	}
+
+	discount, err := resolveCurve(req.CurveID, req.Curve)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if discount != nil {
+		response["curve_npv"] = cashflow.PresentValue(flows, func(t float64) float64 {
+			return discount.Discount(t * yearsPerUnit)
+		})
+	}
+
+	if irr, err := cashflow.IRR(flows); err == nil {
+		response["irr"] = irr
+	} else {
+		response["irr_error"] = err.Error()
+	}
+
+	finance, reinvest := req.FinanceRate, req.ReinvestRate
+	if finance == nil {
+		finance = req.Rate
+	}
+	if reinvest == nil {
+		reinvest = finance
+	}
+	if finance != nil {
+		mirr, err := cashflow.MIRR(flows, *finance, *reinvest)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["mirr"] = mirr
+	}
+
+	payback := gin.H{"simple": nil, "discounted": nil}
+	if t, ok := cashflow.Payback(flows, 0); ok {
+		payback["simple"] = t
+	}
+	if req.Rate != nil {
+		if t, ok := cashflow.Payback(flows, *req.Rate); ok {
+			payback["discounted"] = t
+		}
+	}
+	response["payback"] = payback
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) Amortization(c *gin.Context) {
+	var req AmortizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Frequency == 0 {
+		req.Frequency = 12
+	}
+	if req.Type == "" {
+		req.Type = cashflow.LevelPayment
+	}
+
+	result, err := req.Loan.Schedule()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response := gin.H{"amortization": result}
+
+	freq := float64(req.Frequency)
+	flows := result.Flows()
+	if req.DiscountRate != nil {
+		pv, err := cashflow.NPV(flows, *req.DiscountRate/freq)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["present_value"] = pv
+	}
+	if req.Price != nil {
+		if *req.Price <= 0 {
+			h.SendError(c, http.StatusBadRequest, "price must be positive")
+			return
+		}
+		invested := append([]cashflow.Flow{{Time: 0, Amount: -*req.Price / 100 * req.Principal}}, flows...)
+		irr, err := cashflow.IRR(invested)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["yield"] = irr.Rate * freq
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
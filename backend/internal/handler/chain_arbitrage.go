@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxChainOptions = 5000
+
+func (h *FinMathHandler) ChainArbitrage(c *gin.Context) {
+	var req ChainArbitrageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Options) == 0 || len(req.Options) > maxChainOptions {
+		h.SendError(c, http.StatusBadRequest, "between 1 and 5000 options are required")
+		return
+	}
+
+	rates, err := req.termRates(req.R)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scan, err := finmath.ScanChain(req.S, rates, req.Q, req.Options, req.MinProfit)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, scan)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/credit"
+	"backend/internal/controllers/finmath/curve"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *FinMathHandler) CDS(c *gin.Context) {
+	var req CDSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Quotes) > 50 {
+		h.SendError(c, http.StatusBadRequest, "at most 50 CDS quotes are allowed")
+		return
+	}
+	if err := validateTenors(req.Tenors); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	recovery := 0.4
+	if req.Recovery != nil {
+		recovery = *req.Recovery
+	}
+	if req.Frequency == 0 {
+		req.Frequency = 4
+	}
+
+	discount, err := resolveCurve(req.CurveID, req.Curve)
+	if err == nil && discount == nil {
+		discount, err = curve.NewDiscountCurve([]float64{1}, []float64{math.Exp(-req.Rate)}, "")
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var hazard credit.HazardCurve
+	switch {
+	case req.HazardCurve != nil:
+		hazard = *req.HazardCurve
+		err = hazard.Validate()
+	case len(req.Quotes) > 0:
+		hazard, err = credit.BootstrapHazard(req.Quotes, recovery, req.Frequency, discount)
+	default:
+		h.SendError(c, http.StatusBadRequest, "quotes or hazard_curve is required")
+		return
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenors := req.Tenors
+	if len(tenors) == 0 {
+		tenors = defaultCurveTenors
+	}
+	response := gin.H{
+		"hazard_curve": hazard,
+		"survival":     hazard.Sample(tenors),
+	}
+
+	if req.CDS != nil {
+		contract := *req.CDS
+		if contract.Frequency == 0 {
+			contract.Frequency = req.Frequency
+		}
+		if contract.Recovery == 0 && req.Recovery == nil {
+			contract.Recovery = recovery
+		}
+		result, err := contract.Price(discount, hazard)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["cds"] = result
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) Merton(c *gin.Context) {
+	var req credit.MertonInputs
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := credit.Merton(req)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"backend/internal/controllers/finmath/curve"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxCurveTenors      = 1000
+	maxCurveInstruments = 200
+)
+
+var defaultCurveTenors = []float64{0.25, 0.5, 1, 2, 3, 5, 7, 10, 15, 20, 30}
+
+// rate returns r unless a curve ID is set, in which case it returns the stored
+// curve's zero rate to T
+func (rc RateCurve) rate(r, T float64) (float64, error) {
+	if rc.CurveID == "" {
+		return r, nil
+	}
+	c, err := curve.Load(rc.CurveID)
+	if err != nil {
+		return 0, err
+	}
+	return c.ZeroRate(T), nil
+}
+
+// termRates returns the stored curve when a curve ID is set and a flat r otherwise
+func (rc RateCurve) termRates(r float64) (finmath.Rates, error) {
+	if rc.CurveID == "" {
+		return finmath.FlatRate(r), nil
+	}
+	return curve.Load(rc.CurveID)
+}
+
+// rates looks up rate for each maturity in turn
+func (rc RateCurve) rates(r float64, maturities ...float64) ([]float64, error) {
+	rates := make([]float64, len(maturities))
+	for i, T := range maturities {
+		var err error
+		if rates[i], err = rc.rate(r, T); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
+// resolveCurve loads a stored curve by ID or builds one from inline nodes. It
+// returns nil when neither is given.
+func resolveCurve(id string, inline *curve.DiscountCurve) (curve.Curve, error) {
+	if id != "" {
+		return curve.Load(id)
+	}
+	if inline != nil {
+		return curve.NewDiscountCurve(inline.Times, inline.Discounts, inline.Interpolation)
+	}
+	return nil, nil
+}
+
+func validateTenors(tenors []float64) error {
+	if len(tenors) > maxCurveTenors {
+		return errors.New("at most 1000 tenors can be requested")
+	}
+	for _, t := range tenors {
+		if t < 0 {
+			return errors.New("tenors cannot be negative")
+		}
+	}
+	return nil
+}
+
+func (h *FinMathHandler) CurveBootstrap(c *gin.Context) {
+	var req CurveBootstrapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Instruments) > maxCurveInstruments {
+		h.SendError(c, http.StatusBadRequest, "at most 200 instruments can be bootstrapped")
+		return
+	}
+	if err := validateTenors(req.Tenors); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	interpolation, err := curve.ParseInterpolation(req.Interpolation)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fitted, err := curve.Bootstrap(req.Instruments, interpolation)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenors := req.Tenors
+	if len(tenors) == 0 {
+		tenors = fitted.Times
+	}
+
+	response := gin.H{
+		"curve":  fitted,
+		"points": curve.Sample(fitted, tenors, req.ForwardTenor),
+	}
+	if req.Store {
+		id, err := curve.Save(fitted)
+		if err != nil {
+			h.SendError(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		response["curve_id"] = id
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) CurveFit(c *gin.Context) {
+	var req CurveFitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateTenors(req.Tenors); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.ZeroRates)+len(req.Bonds) > 500 || len(req.Scenarios) > 50 {
+		h.SendError(c, http.StatusBadRequest, "at most 500 observations and 50 scenarios are allowed")
+		return
+	}
+	if req.Model == "" {
+		req.Model = string(curve.NelsonSiegel)
+	}
+
+	fit, err := curve.FitParametric(curve.ParametricModel(req.Model), req.ZeroRates, req.Bonds)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenors := req.Tenors
+	if len(tenors) == 0 {
+		tenors = defaultCurveTenors
+	}
+
+	scenarios := make([]gin.H, len(req.Scenarios))
+	for i, s := range req.Scenarios {
+		shifted := fit.Curve.Shift(s.Level, s.Slope, s.Curvature)
+		scenarios[i] = gin.H{
+			"name":   s.Name,
+			"curve":  shifted,
+			"points": curve.Sample(shifted, tenors, req.ForwardTenor),
+		}
+	}
+
+	response := gin.H{
+		"fit":       fit,
+		"points":    curve.Sample(fit.Curve, tenors, req.ForwardTenor),
+		"scenarios": scenarios,
+	}
+	if req.Store {
+		id, err := curve.Save(fit.Curve)
+		if err != nil {
+			h.SendError(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		response["curve_id"] = id
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) CurveQuery(c *gin.Context) {
+	var req CurveQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Tenors) == 0 {
+		h.SendError(c, http.StatusBadRequest, "tenors are required")
+		return
+	}
+	if err := validateTenors(req.Tenors); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stored, err := curve.Load(c.Param("id"))
+	if err != nil {
+		h.SendError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, curve.Sample(stored, req.Tenors, req.ForwardTenor))
+}
+
+func (h *FinMathHandler) CurveDelete(c *gin.Context) {
+	if !curve.Delete(c.Param("id")) {
+		h.SendError(c, http.StatusNotFound, "unknown curve id: "+c.Param("id"))
+		return
+	}
+	h.SendSuccessWithFields(c, gin.H{"deleted": c.Param("id")})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *FinMathHandler) ExoticOption(c *gin.Context) {
+	var req ExoticOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var err error
+	if req.R, err = req.rate(req.R, req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.S <= 0 || req.V <= 0 {
+		h.SendError(c, http.StatusBadRequest, "spot price and volatility must be positive")
+		return
+	}
+
+	if req.R < 0 {
+		h.SendError(c, http.StatusBadRequest, "risk-free rate cannot be negative")
+		return
+	}
+
+	optionType := c.Param("type")
+
+	var price float64
+	switch optionType {
+	case "barrier":
+		price, err = finmath.BarrierOption(finmath.BarrierType(req.BarrierType), req.IsCall,
+			req.S, req.K, req.Barrier, req.Rebate, req.T, req.R, req.Q, req.V)
+	case "double-barrier":
+		price, err = finmath.DoubleBarrierOption(req.KnockIn, req.IsCall,
+			req.S, req.K, req.LowerBarrier, req.UpperBarrier, req.T, req.R, req.Q, req.V)
+	case "cash-or-nothing":
+		price, err = finmath.CashOrNothing(req.IsCall, req.S, req.K, req.CashPayout, req.T, req.R, req.Q, req.V)
+	case "asset-or-nothing":
+		price, err = finmath.AssetOrNothing(req.IsCall, req.S, req.K, req.T, req.R, req.Q, req.V)
+	case "geometric-asian":
+		price, err = finmath.GeometricAsian(req.IsCall, req.S, req.K, req.T, req.R, req.Q, req.V)
+	case "arithmetic-asian":
+		price, err = finmath.ArithmeticAsian(req.IsCall, req.S, req.K, req.T, req.AveragingStart, req.R, req.Q, req.V)
+	case "floating-lookback":
+		price, err = finmath.FloatingLookback(req.IsCall, req.S, req.ObservedExtreme, req.T, req.R, req.Q, req.V)
+	case "fixed-lookback":
+		price, err = finmath.FixedLookback(req.IsCall, req.S, req.K, req.ObservedExtreme, req.T, req.R, req.Q, req.V)
+	case "compound":
+		// The compound option expires at T but the strike K is paid at the
+		// underlying expiry, so each is discounted at its own rate
+		var r2 float64
+		if r2, err = req.rate(req.R, req.UnderlyingT); err == nil {
+			price, err = finmath.CompoundOption(req.IsCall, req.UnderlyingCall,
+				req.S, req.K, req.CompoundStrike, req.T, req.UnderlyingT, req.R, r2, req.Q, req.V)
+		}
+	case "chooser":
+		price, err = finmath.SimpleChooser(req.S, req.K, req.ChoiceTime, req.T, req.R, req.Q, req.V)
+	case "complex-chooser":
+		var rates []float64
+		if rates, err = req.rates(req.R, req.ChoiceTime, req.CallExpiry, req.PutExpiry); err == nil {
+			price, err = finmath.ComplexChooser(req.S, req.CallStrike, req.PutStrike,
+				req.ChoiceTime, req.CallExpiry, req.PutExpiry, rates[0], rates[1], rates[2], req.Q, req.V)
+		}
+	default:
+		h.SendError(c, http.StatusNotFound, "unknown exotic option type: "+optionType)
+		return
+	}
+
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"type":  optionType,
+		"price": price,
+	})
+}
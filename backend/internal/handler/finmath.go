@@ -3,17 +3,21 @@ package handler
 import (
 	"backend/internal/controllers/finmath"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
 type FinMathHandler struct {
 	*BaseHandler
+	// calendarToken authorises /calendar/load; uploads are disabled without it
+	calendarToken string
 }
 
 func NewFinMathHandler() *FinMathHandler {
 	return &FinMathHandler{
-		BaseHandler: NewBaseHandler(),
+		BaseHandler:   NewBaseHandler(),
+		calendarToken: os.Getenv("CALENDAR_LOAD_TOKEN"),
 	}
 }
 
@@ -24,6 +28,16 @@ func (h *FinMathHandler) BlackScholes(c *gin.Context) {
 		return
 	}
 
+	var err error
+	if req.T, err = req.resolve(req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.R, err = req.rate(req.R, req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err := h.validator.ValidateFinancialParams(req.S, req.K, req.T, req.V); err != nil {
 		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
@@ -48,6 +62,16 @@ func (h *FinMathHandler) ImpliedVolatility(c *gin.Context) {
 		return
 	}
 
+	var err error
+	if req.T, err = req.resolve(req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.R, err = req.rate(req.R, req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if req.S <= 0 || req.K <= 0 || req.T <= 0 || req.MarketPrice <= 0 {
 		h.SendError(c, http.StatusBadRequest, "financial parameters must be positive")
 		return
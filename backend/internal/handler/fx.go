@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/fx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxFXTenors bounds the forward strip, which is returned point by point
+const maxFXTenors = 100
+
+func (h *FinMathHandler) FXForward(c *gin.Context) {
+	var req FXForwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Tenors) > maxFXTenors {
+		h.SendError(c, http.StatusBadRequest, "at most 100 tenors can be requested")
+		return
+	}
+	if err := validateTenors(req.Tenors); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.PipSize == 0 {
+		req.PipSize = 0.0001
+	}
+
+	result, err := fx.Forward(req.Spot, req.DomesticRate, req.ForeignRate, req.T, req.PipSize, req.Compounding)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response := gin.H{"forward": result.Forward, "points": result.Points}
+
+	if len(req.Tenors) > 0 {
+		strip := make([]gin.H, len(req.Tenors))
+		for i, t := range req.Tenors {
+			f, err := fx.Forward(req.Spot, req.DomesticRate, req.ForeignRate, t, req.PipSize, req.Compounding)
+			if err != nil {
+				h.SendError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			strip[i] = gin.H{"time": t, "forward": f.Forward, "points": f.Points}
+		}
+		response["strip"] = strip
+	}
+	if req.MarketForward != nil {
+		rf, err := fx.ImpliedForeignRate(req.Spot, *req.MarketForward, req.DomesticRate, req.T, req.Compounding)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["implied_foreign_rate"] = rf
+	}
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) FXCrossRates(c *gin.Context) {
+	var req FXCrossRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Quotes) == 0 || len(req.Quotes) > 200 || len(req.Pairs) > 200 {
+		h.SendError(c, http.StatusBadRequest, "between 1 and 200 quotes and at most 200 pairs are allowed")
+		return
+	}
+
+	market, err := fx.NewMarket(req.Quotes)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pairs := req.Pairs
+	if len(pairs) == 0 {
+		quoted := map[string]bool{}
+		for _, q := range req.Quotes {
+			quoted[strings.ToUpper(q.Pair)] = true
+		}
+		currencies := market.Currencies()
+		for i, a := range currencies {
+			for _, b := range currencies[i+1:] {
+				if !quoted[a+"/"+b] && !quoted[b+"/"+a] && !quoted[a+b] && !quoted[b+a] {
+					pairs = append(pairs, a+"/"+b)
+				}
+			}
+		}
+	}
+
+	crosses := []fx.CrossRate{}
+	for _, pair := range pairs {
+		base, quote, ok := strings.Cut(pair, "/")
+		if !ok && len(pair) == 6 {
+			base, quote, ok = pair[:3], pair[3:], true
+		}
+		if !ok {
+			h.SendError(c, http.StatusBadRequest, "pairs must look like EUR/USD or EURUSD: "+pair)
+			return
+		}
+		cross, err := market.Cross(base, quote)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		crosses = append(crosses, cross)
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"currencies":  market.Currencies(),
+		"cross_rates": crosses,
+		"arbitrage":   market.TriangularArbitrage(req.MinProfit),
+	})
+}
+
+func (h *FinMathHandler) FXOption(c *gin.Context) {
+	var req FXOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Convention == "" {
+		req.Convention = fx.SpotDelta
+	}
+	if req.Strike == 0 && req.Delta == nil {
+		h.SendError(c, http.StatusBadRequest, "strike or delta is required")
+		return
+	}
+
+	atm := gin.H{}
+	for _, kind := range []string{fx.ATMSpot, fx.ATMForward, fx.ATMDeltaNeutral} {
+		k, err := fx.ATMStrike(kind, req.Spot, req.T, req.DomesticRate, req.ForeignRate, req.Volatility, req.Convention)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		atm[kind] = k
+	}
+	response := gin.H{"forward": atm[fx.ATMForward], "atm_strikes": atm}
+
+	strike := req.Strike
+	if req.Delta != nil {
+		k, err := fx.StrikeFromDelta(req.IsCall, *req.Delta, req.Spot, req.T, req.DomesticRate, req.ForeignRate, req.Volatility, req.Convention)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["strike_from_delta"] = k
+		if strike == 0 {
+			strike = k
+		}
+	}
+
+	price, err := fx.GarmanKohlhagen(req.IsCall, req.Spot, strike, req.T, req.DomesticRate, req.ForeignRate, req.Volatility)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	deltas := gin.H{}
+	for _, conv := range fx.DeltaConventions {
+		d, err := fx.Delta(req.IsCall, req.Spot, strike, req.T, req.DomesticRate, req.ForeignRate, req.Volatility, conv)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		deltas[string(conv)] = d
+	}
+	response["strike"] = strike
+	response["price"] = price
+	response["deltas"] = deltas
+	h.SendSuccessWithFields(c, response)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxHestonQuotes = 500
+
+func (h *FinMathHandler) HestonPrice(c *gin.Context) {
+	var req HestonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var err error
+	if req.R, err = req.rate(req.R, req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.S <= 0 || req.K <= 0 || req.T <= 0 {
+		h.SendError(c, http.StatusBadRequest, "financial parameters must be positive")
+		return
+	}
+
+	price, err := finmath.HestonPrice(req.Params, req.S, req.K, req.T, req.R, req.Q, req.IsCall)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"price":        price,
+		"feller_ratio": req.Params.FellerRatio(),
+	})
+}
+
+func (h *FinMathHandler) HestonCalibrate(c *gin.Context) {
+	var req HestonCalibrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.S <= 0 {
+		h.SendError(c, http.StatusBadRequest, "spot price must be positive")
+		return
+	}
+
+	if len(req.Quotes) > maxHestonQuotes {
+		h.SendError(c, http.StatusBadRequest, "at most 500 quotes can be calibrated")
+		return
+	}
+
+	rates, err := req.termRates(req.R)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quotes := make([]finmath.HestonQuote, len(req.Quotes))
+	for i, q := range req.Quotes {
+		quotes[i] = finmath.HestonQuote{Strike: q.Strike, Expiry: q.Expiry, ImpliedVol: q.ImpliedVol}
+	}
+
+	result, err := finmath.CalibrateHeston(req.S, rates, req.Q, quotes, req.Initial)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
@@ -18,12 +18,56 @@ func Introduction(c *gin.Context) {
 			"/api/dist/normal-cdf",
 			"/api/timeseries/moving-average",
 			"/api/timeseries/exponential-average",
+			"/api/timeseries/futures/carry",
+			"/api/timeseries/futures/continuous",
 			"/api/opt/golden-section",
 			"/api/finmath/black-scholes",
 			"/api/finmath/implied-volatility",
+			"/api/finmath/exotic/{type}",
+			"/api/finmath/heston",
+			"/api/finmath/heston/calibrate",
+			"/api/finmath/sabr",
+			"/api/finmath/sabr/calibrate",
+			"/api/finmath/vol-surface",
+			"/api/finmath/local-vol",
+			"/api/finmath/local-vol/price",
+			"/api/finmath/jump-diffusion",
+			"/api/finmath/bond/price",
+			"/api/finmath/bond/yield",
+			"/api/finmath/bond/z-spread",
+			"/api/finmath/calendar/year-fraction",
+			"/api/finmath/calendar/adjust",
+			"/api/finmath/calendar/holidays",
+			"/api/finmath/calendar/schedule",
+			"/api/finmath/calendar/load",
+			"/api/finmath/curve/bootstrap",
+			"/api/finmath/curve/fit",
+			"/api/finmath/curve/{id}",
+			"/api/finmath/swap",
+			"/api/finmath/short-rate",
+			"/api/finmath/short-rate/calibrate",
+			"/api/finmath/cap-floor",
+			"/api/finmath/swaption",
+			"/api/finmath/cashflow",
+			"/api/finmath/amortization",
+			"/api/finmath/credit/cds",
+			"/api/finmath/credit/merton",
+			"/api/finmath/strategy",
+			"/api/finmath/chain-arbitrage",
+			"/api/finmath/fx/forward",
+			"/api/finmath/fx/cross-rates",
+			"/api/finmath/fx/option",
 			"/api/calculus/derivative",
 			"/api/calculus/integral",
 			"/api/sim/monte-carlo",
+			"/api/sim/jump-diffusion",
+			"/api/sim/short-rate",
+			"/api/valuation/dcf",
+			"/api/valuation/ratios",
+			"/api/valuation/scores",
+			"/api/screener/universe",
+			"/api/screener/universes",
+			"/api/screener/screen",
 			"/api/finance/news",
 			"/api/finance/sources",
 		},
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *FinMathHandler) JumpDiffusion(c *gin.Context) {
+	var req JumpDiffusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var err error
+	if req.R, err = req.rate(req.R, req.T); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.ValidateFinancialParams(req.S, req.K, req.T, req.V); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var price float64
+	switch req.Model {
+	case "merton":
+		if req.Merton == nil {
+			h.SendError(c, http.StatusBadRequest, "merton jump parameters are required")
+			return
+		}
+		price, err = finmath.MertonJumpDiffusion(req.IsCall, req.S, req.K, req.T, req.R, req.Q, req.V, *req.Merton)
+	case "kou":
+		if req.Kou == nil {
+			h.SendError(c, http.StatusBadRequest, "kou jump parameters are required")
+			return
+		}
+		price, err = finmath.KouJumpDiffusion(req.IsCall, req.S, req.K, req.T, req.R, req.Q, req.V, *req.Kou)
+	default:
+		h.SendError(c, http.StatusBadRequest, "model must be merton or kou")
+		return
+	}
+
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"model": req.Model,
+		"price": price,
+	})
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxLocalVolSteps     = 20_000_000
+	maxLocalVolSmoothing = 10
+)
+
+func (h *FinMathHandler) LocalVol(c *gin.Context) {
+	var req LocalVolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lv, _, err := h.buildLocalVol(req, 0)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, lv)
+}
+
+func (h *FinMathHandler) LocalVolPrice(c *gin.Context) {
+	var req LocalVolPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.T <= 0 {
+		h.SendError(c, http.StatusBadRequest, "time to expiry must be positive")
+		return
+	}
+
+	if req.Steps == 0 {
+		req.Steps = int(math.Ceil(252 * req.T))
+	}
+	if req.Paths == 0 {
+		req.Paths = 10000
+	}
+	if !pathGridFits(req.Steps, req.Paths, maxLocalVolSteps, false) {
+		h.SendError(c, http.StatusBadRequest, "steps and paths must be positive and steps*paths at most 20,000,000")
+		return
+	}
+
+	streams, err := req.streams()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lv, surface, err := h.buildLocalVol(req.LocalVolRequest, req.T)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := finmath.LocalVolMonteCarlo(lv, req.Payoff, surface.Spot, req.T, surface, surface.Dividend, req.Steps, req.Paths, streams)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+// buildLocalVol resolves the implied surface (fitting it from quotes if needed) and
+// extracts local vol on the requested grid, defaulting to a grid that covers horizon
+func (h *FinMathHandler) buildLocalVol(req LocalVolRequest, horizon float64) (*finmath.LocalVolSurface, *finmath.VolSurface, error) {
+	rates, err := req.termRates(req.R)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var surface finmath.VolSurface
+	if req.Surface != nil {
+		surface = *req.Surface
+		if req.CurveID != "" {
+			surface.SetRates(rates)
+		}
+	} else {
+		if len(req.Quotes) == 0 {
+			return nil, nil, errors.New("quotes or a fitted surface are required")
+		}
+		if len(req.Quotes) > maxSurfaceQuotes {
+			return nil, nil, errors.New("at most 5000 quotes can be fitted")
+		}
+		fit, err := finmath.BuildVolSurface(req.S, rates, req.Q, finmath.SurfaceModel(req.Model), req.Quotes)
+		if err != nil {
+			return nil, nil, err
+		}
+		surface = fit.Surface
+	}
+	if err := surface.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	lastExpiry := surface.Slices[len(surface.Slices)-1].Expiry
+	horizon = math.Max(horizon, lastExpiry)
+
+	if len(req.Times) == 0 {
+		for i := 1; i <= 25; i++ {
+			req.Times = append(req.Times, horizon*float64(i)/25)
+		}
+	}
+	if len(req.Spots) == 0 {
+		atmVol, err := surface.ImpliedVol(surface.Forward(horizon), horizon)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Local vol is convex in spot, so a coarse grid interpolated linearly
+		// overstates it; 161 points keep the bias well inside MC error
+		width := 4 * atmVol * math.Sqrt(horizon)
+		for i := 0; i <= 160; i++ {
+			req.Spots = append(req.Spots, surface.Spot*math.Exp(-width+2*width*float64(i)/160))
+		}
+	}
+
+	if err := validateSurfaceGrid(req.Spots, req.Times); err != nil {
+		return nil, nil, err
+	}
+
+	smoothing := 1
+	if req.Smoothing != nil {
+		smoothing = *req.Smoothing
+	}
+	if smoothing < 0 || smoothing > maxLocalVolSmoothing {
+		return nil, nil, errors.New("smoothing must be between 0 and 10 passes")
+	}
+
+	lv, err := finmath.DupireLocalVol(&surface, req.Spots, req.Times, req.MinVol, req.MaxVol, smoothing)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lv, &surface, nil
+}
@@ -24,8 +24,9 @@ func (h *OptimizationHandler) GoldenSectionSearch(c *gin.Context) {
 		return
 	}
 
-	if !h.validator.IsValidFunction(req.Function) {
-		h.SendError(c, http.StatusBadRequest, "invalid function")
+	f, err := h.validator.ParseFunction(req.Function, "(x-2)^2")
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -39,7 +40,7 @@ func (h *OptimizationHandler) GoldenSectionSearch(c *gin.Context) {
 		return
 	}
 
-	result, err := opt.GoldenSectionSearch(req.Lower, req.Upper, req.Tol)
+	result, err := opt.GoldenSectionSearch(f, req.Lower, req.Upper, req.Tol)
 	if err != nil {
 		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
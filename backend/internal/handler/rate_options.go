@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"backend/internal/controllers/finmath/curve"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func rateOptionCurve(id string, inline *curve.DiscountCurve) (curve.Curve, error) {
+	c, err := resolveCurve(id, inline)
+	if err == nil && c == nil {
+		err = errors.New("curve or curve_id is required")
+	}
+	return c, err
+}
+
+func (v RateOptionVol) termStructure() finmath.RateVol {
+	if v.VolTermStructure != nil {
+		return *v.VolTermStructure
+	}
+	return finmath.RateVol{Vols: []float64{v.Vol}}
+}
+
+func (h *FinMathHandler) CapFloor(c *gin.Context) {
+	var req CapFloorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	discount, err := rateOptionCurve(req.CurveID, req.Curve)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Model == "" {
+		req.Model = finmath.Black
+	}
+
+	var result finmath.CapFloorResult
+	if req.MarketPrice != nil {
+		result, err = req.CapFloor.ImpliedVol(discount, *req.MarketPrice)
+	} else {
+		result, err = req.CapFloor.Price(discount, req.termStructure())
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+func (h *FinMathHandler) Swaption(c *gin.Context) {
+	var req SwaptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	discount, err := rateOptionCurve(req.CurveID, req.Curve)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Model == "" {
+		req.Model = finmath.Black
+	}
+
+	var result finmath.SwaptionResult
+	switch {
+	case req.MarketPrice != nil:
+		result, err = req.EuropeanSwaption.ImpliedVol(discount, *req.MarketPrice)
+	case req.VolTermStructure != nil:
+		vol := req.termStructure()
+		if err = vol.Validate(); err == nil {
+			result, err = req.EuropeanSwaption.Price(discount, vol.At(req.Expiry))
+		}
+	default:
+		result, err = req.EuropeanSwaption.Price(discount, req.Vol)
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
@@ -15,6 +15,8 @@ type Handlers struct {
 	FinMath      *FinMathHandler
 	Calculus     *CalculusHandler
 	Simulation   *SimulationHandler
+	Valuation    *ValuationHandler
+	Screener     *ScreenerHandler
 }
 
 func NewHandlers() *Handlers {
@@ -27,6 +29,8 @@ func NewHandlers() *Handlers {
 		FinMath:      NewFinMathHandler(),
 		Calculus:     NewCalculusHandler(),
 		Simulation:   NewSimulationHandler(),
+		Valuation:    NewValuationHandler(),
+		Screener:     NewScreenerHandler(),
 	}
 }
 
@@ -42,12 +46,56 @@ func (h *Handlers) Introduction(c *gin.Context) {
 			"/api/dist/normal-cdf",
 			"/api/timeseries/moving-average",
 			"/api/timeseries/exponential-average",
+			"/api/timeseries/futures/carry",
+			"/api/timeseries/futures/continuous",
 			"/api/opt/golden-section",
 			"/api/finmath/black-scholes",
 			"/api/finmath/implied-volatility",
+			"/api/finmath/exotic/{type}",
+			"/api/finmath/heston",
+			"/api/finmath/heston/calibrate",
+			"/api/finmath/sabr",
+			"/api/finmath/sabr/calibrate",
+			"/api/finmath/vol-surface",
+			"/api/finmath/local-vol",
+			"/api/finmath/local-vol/price",
+			"/api/finmath/jump-diffusion",
+			"/api/finmath/bond/price",
+			"/api/finmath/bond/yield",
+			"/api/finmath/bond/z-spread",
+			"/api/finmath/calendar/year-fraction",
+			"/api/finmath/calendar/adjust",
+			"/api/finmath/calendar/holidays",
+			"/api/finmath/calendar/schedule",
+			"/api/finmath/calendar/load",
+			"/api/finmath/curve/bootstrap",
+			"/api/finmath/curve/fit",
+			"/api/finmath/curve/{id}",
+			"/api/finmath/swap",
+			"/api/finmath/short-rate",
+			"/api/finmath/short-rate/calibrate",
+			"/api/finmath/cap-floor",
+			"/api/finmath/swaption",
+			"/api/finmath/cashflow",
+			"/api/finmath/amortization",
+			"/api/finmath/credit/cds",
+			"/api/finmath/credit/merton",
+			"/api/finmath/strategy",
+			"/api/finmath/chain-arbitrage",
+			"/api/finmath/fx/forward",
+			"/api/finmath/fx/cross-rates",
+			"/api/finmath/fx/option",
 			"/api/calculus/derivative",
 			"/api/calculus/integral",
 			"/api/sim/monte-carlo",
+			"/api/sim/jump-diffusion",
+			"/api/sim/short-rate",
+			"/api/valuation/dcf",
+			"/api/valuation/ratios",
+			"/api/valuation/scores",
+			"/api/screener/universe",
+			"/api/screener/universes",
+			"/api/screener/screen",
 		},
 	})
 }
@@ -84,6 +132,8 @@ func (h *Handlers) RegisterRoutes(r *gin.Engine) {
 	{
 		timeseries.POST("/moving-average", h.TimeSeries.MovingAverage)
 		timeseries.POST("/exponential-average", h.TimeSeries.ExponentialAverage)
+		timeseries.POST("/futures/carry", h.TimeSeries.FuturesCarry)
+		timeseries.POST("/futures/continuous", h.TimeSeries.ContinuousFutures)
 	}
 
 	// Optimization routes
@@ -97,6 +147,41 @@ func (h *Handlers) RegisterRoutes(r *gin.Engine) {
 	{
 		finmath.POST("/black-scholes", h.FinMath.BlackScholes)
 		finmath.POST("/implied-volatility", h.FinMath.ImpliedVolatility)
+		finmath.POST("/exotic/:type", h.FinMath.ExoticOption)
+		finmath.POST("/heston", h.FinMath.HestonPrice)
+		finmath.POST("/heston/calibrate", h.FinMath.HestonCalibrate)
+		finmath.POST("/sabr", h.FinMath.SABRVol)
+		finmath.POST("/sabr/calibrate", h.FinMath.SABRCalibrate)
+		finmath.POST("/vol-surface", h.FinMath.VolSurface)
+		finmath.POST("/local-vol", h.FinMath.LocalVol)
+		finmath.POST("/local-vol/price", h.FinMath.LocalVolPrice)
+		finmath.POST("/jump-diffusion", h.FinMath.JumpDiffusion)
+		finmath.POST("/bond/price", h.FinMath.BondPrice)
+		finmath.POST("/bond/yield", h.FinMath.BondYield)
+		finmath.POST("/bond/z-spread", h.FinMath.BondZSpread)
+		finmath.POST("/calendar/year-fraction", h.FinMath.YearFraction)
+		finmath.POST("/calendar/adjust", h.FinMath.AdjustDate)
+		finmath.POST("/calendar/holidays", h.FinMath.Holidays)
+		finmath.POST("/calendar/schedule", h.FinMath.Schedule)
+		finmath.POST("/calendar/load", h.FinMath.LoadCalendar)
+		finmath.POST("/curve/bootstrap", h.FinMath.CurveBootstrap)
+		finmath.POST("/curve/fit", h.FinMath.CurveFit)
+		finmath.POST("/curve/:id", h.FinMath.CurveQuery)
+		finmath.DELETE("/curve/:id", h.FinMath.CurveDelete)
+		finmath.POST("/swap", h.FinMath.Swap)
+		finmath.POST("/short-rate", h.FinMath.ShortRate)
+		finmath.POST("/short-rate/calibrate", h.FinMath.ShortRateCalibrate)
+		finmath.POST("/cap-floor", h.FinMath.CapFloor)
+		finmath.POST("/swaption", h.FinMath.Swaption)
+		finmath.POST("/cashflow", h.FinMath.Cashflow)
+		finmath.POST("/amortization", h.FinMath.Amortization)
+		finmath.POST("/credit/cds", h.FinMath.CDS)
+		finmath.POST("/credit/merton", h.FinMath.Merton)
+		finmath.POST("/strategy", h.FinMath.Strategy)
+		finmath.POST("/chain-arbitrage", h.FinMath.ChainArbitrage)
+		finmath.POST("/fx/forward", h.FinMath.FXForward)
+		finmath.POST("/fx/cross-rates", h.FinMath.FXCrossRates)
+		finmath.POST("/fx/option", h.FinMath.FXOption)
 	}
 
 	// Calculus routes
@@ -110,5 +195,24 @@ func (h *Handlers) RegisterRoutes(r *gin.Engine) {
 	sim := api.Group("/sim")
 	{
 		sim.POST("/monte-carlo", h.Simulation.MonteCarlo)
+		sim.POST("/jump-diffusion", h.Simulation.JumpDiffusionPaths)
+		sim.POST("/short-rate", h.Simulation.ShortRatePaths)
+	}
+
+	// Valuation routes
+	valuation := api.Group("/valuation")
+	{
+		valuation.POST("/dcf", h.Valuation.DCF)
+		valuation.POST("/ratios", h.Valuation.Ratios)
+		valuation.POST("/scores", h.Valuation.Scores)
+	}
+
+	// Screener routes
+	screener := api.Group("/screener")
+	{
+		screener.POST("/universe", h.Screener.LoadUniverse)
+		screener.GET("/universes", h.Screener.ListUniverses)
+		screener.DELETE("/universe/:name", h.Screener.DeleteUniverse)
+		screener.POST("/screen", h.Screener.Screen)
 	}
 }
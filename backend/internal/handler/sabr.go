@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxSABRSlices      = 100
+	maxSABRSliceQuotes = 500
+)
+
+func (h *FinMathHandler) SABRVol(c *gin.Context) {
+	var req SABRVolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vol, err := finmath.SABRVol(finmath.SABRExpansion(req.Expansion), req.Params, req.Forward, req.K, req.T)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, vol)
+}
+
+func (h *FinMathHandler) SABRCalibrate(c *gin.Context) {
+	var req SABRCalibrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Slices) == 0 || len(req.Slices) > maxSABRSlices {
+		h.SendError(c, http.StatusBadRequest, "between 1 and 100 expiry slices are required")
+		return
+	}
+	for _, slice := range req.Slices {
+		if len(slice.Quotes) > maxSABRSliceQuotes {
+			h.SendError(c, http.StatusBadRequest, "at most 500 quotes per slice can be calibrated")
+			return
+		}
+	}
+
+	expansion := finmath.SABRExpansion(req.Expansion)
+	results := make([]finmath.SABRCalibrationResult, 0, len(req.Slices))
+
+	for _, slice := range req.Slices {
+		r, err := req.rate(req.R, slice.Expiry)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		forward := slice.Forward
+		if forward <= 0 {
+			if req.S <= 0 {
+				h.SendError(c, http.StatusBadRequest, "each slice needs a forward, or a spot price must be given")
+				return
+			}
+			forward = req.S * math.Exp((r-req.Q)*slice.Expiry)
+		}
+
+		strikes := make([]float64, len(slice.Quotes))
+		vols := make([]float64, len(slice.Quotes))
+		for i, q := range slice.Quotes {
+			strikes[i] = q.Strike
+			vols[i] = q.ImpliedVol
+			if vols[i] > 0 || q.Price <= 0 {
+				continue
+			}
+
+			if expansion == finmath.SABRNormal {
+				h.SendError(c, http.StatusBadRequest, "quotes for the normal expansion must be given as implied vols")
+				return
+			}
+			// Back the Black vol out of the forward-equivalent spot
+			spot := forward * math.Exp(-(r-req.Q)*slice.Expiry)
+			vol, err := finmath.ImpliedVolatilityWithCarry(spot, q.Strike, slice.Expiry, r, req.Q, q.Price, q.IsCall)
+			if err != nil {
+				h.SendError(c, http.StatusBadRequest, fmt.Sprintf("expiry %g strike %g: %s", slice.Expiry, q.Strike, err.Error()))
+				return
+			}
+			vols[i] = vol
+		}
+
+		result, err := finmath.CalibrateSABR(expansion, forward, slice.Expiry, req.Beta, strikes, vols)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, fmt.Sprintf("expiry %g: %s", slice.Expiry, err.Error()))
+			return
+		}
+		results = append(results, result)
+	}
+
+	h.SendSuccess(c, results)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"backend/internal/controllers/screener"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScreenerHandler struct {
+	*BaseHandler
+}
+
+func NewScreenerHandler() *ScreenerHandler {
+	return &ScreenerHandler{
+		BaseHandler: NewBaseHandler(),
+	}
+}
+
+func (h *ScreenerHandler) LoadUniverse(c *gin.Context) {
+	var req LoadUniverseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var u *screener.Universe
+	var err error
+	switch strings.ToLower(req.Format) {
+	case "csv":
+		u, err = screener.ParseCSV(req.Name, []byte(req.Data))
+	case "json":
+		u, err = screener.ParseJSON(req.Name, []byte(req.Data))
+	default:
+		h.SendError(c, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+	if err == nil {
+		err = screener.Register(u)
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"universe":   u.Name,
+		"securities": len(u.Securities),
+		"fields":     u.Fields(),
+	})
+}
+
+func (h *ScreenerHandler) ListUniverses(c *gin.Context) {
+	h.SendSuccess(c, screener.Names())
+}
+
+func (h *ScreenerHandler) DeleteUniverse(c *gin.Context) {
+	if !screener.Delete(c.Param("name")) {
+		h.SendError(c, http.StatusNotFound, "unknown universe: "+c.Param("name"))
+		return
+	}
+	h.SendSuccessWithFields(c, gin.H{"deleted": c.Param("name")})
+}
+
+func (h *ScreenerHandler) Screen(c *gin.Context) {
+	var req ScreenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var u *screener.Universe
+	switch {
+	case req.Universe != "":
+		var err error
+		if u, err = screener.Lookup(req.Universe); err != nil {
+			h.SendError(c, http.StatusNotFound, err.Error())
+			return
+		}
+	case len(req.Securities) > 0:
+		u = &screener.Universe{Name: "inline", Securities: req.Securities}
+	default:
+		h.SendError(c, http.StatusBadRequest, "universe or securities is required")
+		return
+	}
+
+	result, err := screener.Run(u, req.Screen)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
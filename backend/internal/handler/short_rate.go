@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/shortrate"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxTreeSteps = 2000
+
+func (h *FinMathHandler) ShortRate(c *gin.Context) {
+	var req ShortRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateTenors(req.Maturities); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	initial, err := resolveCurve(req.CurveID, req.Curve)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	model, err := shortrate.New(shortrate.ModelType(req.Model), req.Params, initial)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	maturities := req.Maturities
+	if len(maturities) == 0 {
+		maturities = defaultCurveTenors
+	}
+	bonds := make([]gin.H, len(maturities))
+	for i, T := range maturities {
+		df := model.Discount(T)
+		zero := 0.0
+		if T > 0 {
+			zero = -math.Log(df) / T
+		}
+		bonds[i] = gin.H{"maturity": T, "discount_factor": df, "zero_rate": zero}
+	}
+	response := gin.H{"model": req.Model, "bonds": bonds}
+
+	if o := req.BondOption; o != nil {
+		if o.Expiry <= 0 || o.BondMaturity <= o.Expiry || o.Strike <= 0 {
+			h.SendError(c, http.StatusBadRequest, "bond option needs 0 < expiry < bond_maturity and a positive strike")
+			return
+		}
+		result := gin.H{"price": model.BondOption(o.IsCall, o.Expiry, o.BondMaturity, o.Strike)}
+		if o.TreeSteps > 0 {
+			hw, ok := model.(shortrate.HullWhite)
+			if !ok {
+				h.SendError(c, http.StatusBadRequest, "tree pricing is only available for hull_white")
+				return
+			}
+			if o.TreeSteps > maxTreeSteps || o.BondMaturity/o.Expiry*float64(o.TreeSteps) > 5000 {
+				h.SendError(c, http.StatusBadRequest, "tree_steps must be at most 2000 and the tree at most 5000 steps to bond maturity")
+				return
+			}
+			price, err := shortrate.TreeBondOption(hw, o.IsCall, o.American, o.Expiry, o.BondMaturity, o.Strike, o.TreeSteps)
+			if err != nil {
+				h.SendError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			result["tree_price"] = price
+		}
+		response["bond_option"] = result
+	}
+
+	if cp := req.Cap; cp != nil {
+		if cp.Maturity*float64(cp.Frequency) > 1200 {
+			h.SendError(c, http.StatusBadRequest, "cap is limited to 1200 caplets")
+			return
+		}
+		price, err := shortrate.Cap(model, cp.Maturity, cp.Strike, cp.Frequency)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["cap"] = gin.H{"price": price}
+	}
+
+	if s := req.Swaption; s != nil {
+		if s.Tenor*float64(s.Frequency) > 1200 {
+			h.SendError(c, http.StatusBadRequest, "swaption is limited to 1200 fixed periods")
+			return
+		}
+		price, err := shortrate.Swaption(model, s.IsPayer, s.Expiry, s.Tenor, s.Strike, s.Frequency)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["swaption"] = gin.H{"price": price}
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *FinMathHandler) ShortRateCalibrate(c *gin.Context) {
+	var req ShortRateCalibrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Quotes) > 100 {
+		h.SendError(c, http.StatusBadRequest, "at most 100 quotes are allowed")
+		return
+	}
+	for _, q := range req.Quotes {
+		if q.Maturity*float64(q.Frequency) > 400 || q.Tenor*float64(q.Frequency) > 400 {
+			h.SendError(c, http.StatusBadRequest, "quotes are limited to 400 periods")
+			return
+		}
+	}
+	initial, err := resolveCurve(req.CurveID, req.Curve)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := shortrate.Calibrate(shortrate.ModelType(req.Model), req.Quotes, initial, req.Initial)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
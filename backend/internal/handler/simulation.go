@@ -1,12 +1,33 @@
 package handler
 
 import (
+	"backend/internal/controllers/finmath/shortrate"
 	"backend/internal/controllers/sim"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxReturnedPathPoints = 100_000
+	maxMonteCarloSamples  = 10_000_000
+)
+
+// pathGridFits reports whether steps and paths are positive and the simulated
+// grid stays within limit points. With withStart each path also holds its
+// initial value. The product is compared by division so it cannot overflow.
+func pathGridFits(steps, paths, limit int, withStart bool) bool {
+	if steps <= 0 || paths <= 0 || steps > limit {
+		return false
+	}
+	points := steps
+	if withStart {
+		points++
+	}
+	return paths <= limit/points
+}
+
 type SimulationHandler struct {
 	*BaseHandler
 }
@@ -24,21 +45,147 @@ func (h *SimulationHandler) MonteCarlo(c *gin.Context) {
 		return
 	}
 
-	if !h.validator.IsValidFunction(req.Function) {
-		h.SendError(c, http.StatusBadRequest, "invalid function")
+	if req.Samples <= 0 || req.Samples > maxMonteCarloSamples {
+		h.SendError(c, http.StatusBadRequest, "samples must be between 1 and 10,000,000")
 		return
 	}
 
-	if req.Samples <= 0 {
-		h.SendError(c, http.StatusBadRequest, "samples must be positive")
+	streams, err := req.streams()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	result, err := sim.MonteCarlo(req.Samples)
+	// pi_estimation predates expressions and keeps its original estimator
+	var result sim.MonteCarloResult
+	if strings.EqualFold(strings.TrimSpace(req.Function), "pi_estimation") {
+		result, err = sim.MonteCarlo(req.Samples, streams)
+	} else {
+		f, parseErr := h.validator.ParseFunction(req.Function, "")
+		if parseErr != nil {
+			h.SendError(c, http.StatusBadRequest, parseErr.Error())
+			return
+		}
+		lower, upper := 0.0, 1.0
+		if v, ok := req.Parameters["lower"].(float64); ok {
+			lower = v
+		}
+		if v, ok := req.Parameters["upper"].(float64); ok {
+			upper = v
+		}
+		result, err = sim.MonteCarloIntegral(f, lower, upper, req.Samples, streams)
+	}
 	if err != nil {
 		h.SendError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.SendSuccess(c, result)
+	h.sendSimulation(c, result, streams)
+}
+
+func (h *SimulationHandler) JumpDiffusionPaths(c *gin.Context) {
+	var req JumpPathsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !pathGridFits(req.Steps, req.Paths, maxReturnedPathPoints, true) {
+		h.SendError(c, http.StatusBadRequest, "steps and paths must be positive and (steps+1)*paths at most 100,000")
+		return
+	}
+
+	streams, err := req.streams()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var paths [][]float64
+	switch req.Model {
+	case "merton":
+		if req.Merton == nil {
+			h.SendError(c, http.StatusBadRequest, "merton jump parameters are required")
+			return
+		}
+		j := req.Merton
+		paths, err = sim.MertonJumpPaths(req.S, req.Mu, req.V, j.Lambda, j.Mean, j.Vol, req.T, req.Steps, req.Paths, streams)
+	case "kou":
+		if req.Kou == nil {
+			h.SendError(c, http.StatusBadRequest, "kou jump parameters are required")
+			return
+		}
+		j := req.Kou
+		paths, err = sim.KouJumpPaths(req.S, req.Mu, req.V, j.Lambda, j.P, j.Eta1, j.Eta2, req.T, req.Steps, req.Paths, streams)
+	default:
+		h.SendError(c, http.StatusBadRequest, "model must be merton or kou")
+		return
+	}
+
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendSimulation(c, paths, streams)
+}
+
+func (h *SimulationHandler) ShortRatePaths(c *gin.Context) {
+	var req ShortRatePathsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !pathGridFits(req.Steps, req.Paths, maxReturnedPathPoints, true) {
+		h.SendError(c, http.StatusBadRequest, "steps and paths must be positive and (steps+1)*paths at most 100,000")
+		return
+	}
+
+	streams, err := req.streams()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := req.Params
+	var paths [][]float64
+	switch shortrate.ModelType(req.Model) {
+	case shortrate.VasicekModel:
+		paths, err = sim.VasicekPaths(p.R0, p.Kappa, p.Theta, p.Sigma, req.T, req.Steps, req.Paths, streams)
+	case shortrate.CIRModel:
+		paths, err = sim.CIRPaths(p.R0, p.Kappa, p.Theta, p.Sigma, req.T, req.Steps, req.Paths, streams)
+	case shortrate.HullWhiteModel:
+		initial, cerr := resolveCurve(req.CurveID, req.Curve)
+		if cerr != nil {
+			h.SendError(c, http.StatusBadRequest, cerr.Error())
+			return
+		}
+		model, merr := shortrate.New(shortrate.HullWhiteModel, p, initial)
+		if merr != nil {
+			h.SendError(c, http.StatusBadRequest, merr.Error())
+			return
+		}
+		hw := model.(shortrate.HullWhite)
+		paths, err = sim.HullWhitePaths(hw.A, hw.Sigma, req.T, req.Steps, req.Paths, hw.Shift, streams)
+	default:
+		h.SendError(c, http.StatusBadRequest, "model must be vasicek, cir or hull_white")
+		return
+	}
+
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendSimulation(c, paths, streams)
+}
+
+// sendSimulation echoes the seed and generator so the run can be reproduced
+func (h *SimulationHandler) sendSimulation(c *gin.Context, result interface{}, streams *sim.Streams) {
+	h.SendSuccessWithFields(c, gin.H{
+		"result":    result,
+		"seed":      streams.Seed(),
+		"generator": streams.Generator(),
+	})
 }
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxStrategyPoints = 2001
+
+func (h *FinMathHandler) Strategy(c *gin.Context) {
+	var req StrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Points == 0 {
+		req.Points = 101
+	}
+	if req.Points > maxStrategyPoints {
+		h.SendError(c, http.StatusBadRequest, "at most 2001 spot points are allowed")
+		return
+	}
+	if req.MinSpot == 0 && req.MaxSpot == 0 {
+		lo, hi := req.Spot, req.Spot
+		for _, l := range req.Legs {
+			if l.Strike > 0 {
+				lo, hi = math.Min(lo, l.Strike), math.Max(hi, l.Strike)
+			}
+		}
+		req.MinSpot, req.MaxSpot = lo/2, hi*1.5
+	}
+
+	if req.CurveID != "" {
+		rates, err := req.termRates(req.R)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Strategy.Rates = rates
+	}
+
+	result, err := req.Strategy.Analyze(req.MinSpot, req.MaxSpot, req.Points)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
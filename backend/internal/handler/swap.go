@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath/calendar"
+	"backend/internal/controllers/finmath/curve"
+	"backend/internal/controllers/finmath/swaps"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Swap prices the request's instrument. Notional defaults to 1,000,000, fixed legs
+// to annual 30/360 and float legs to quarterly ACT/360.
+func (h *FinMathHandler) Swap(c *gin.Context) {
+	var req SwapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Notional == 0 {
+		req.Notional = 1_000_000
+	}
+
+	var result interface{}
+	var err error
+	switch req.Type {
+	case "irs", "basis":
+		var swap swaps.Swap
+		if swap, err = buildSwap(req); err == nil {
+			result, err = swap.Price()
+		}
+	case "fra":
+		var fra swaps.FRA
+		if fra, err = buildFRA(req); err == nil {
+			result, err = fra.Price()
+		}
+	default:
+		err = errors.New("type must be irs, basis or fra")
+	}
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+type swapDates struct {
+	valuation, start, end time.Time
+	calendar              *calendar.Calendar
+	convention            calendar.BusinessDayConvention
+}
+
+func parseSwapDates(req SwapRequest) (swapDates, error) {
+	var d swapDates
+	var err error
+	if d.start, err = parseDate("start_date", req.StartDate); err != nil {
+		return d, err
+	}
+	if d.end, err = parseDate("end_date", req.EndDate); err != nil {
+		return d, err
+	}
+	if d.end.Sub(d.start) > 100*366*24*time.Hour {
+		return d, errors.New("instruments cannot run longer than 100 years")
+	}
+	d.valuation = calendar.Date(time.Now())
+	if req.ValuationDate != "" {
+		if d.valuation, err = parseDate("valuation_date", req.ValuationDate); err != nil {
+			return d, err
+		}
+	}
+	if err := calendar.CheckSpan(d.valuation, d.end); err != nil {
+		return d, err
+	}
+	if d.calendar, err = calendar.Lookup(req.Calendar); err != nil {
+		return d, err
+	}
+	if d.convention, err = calendar.ParseConvention(req.Convention); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+func buildSwap(req SwapRequest) (swaps.Swap, error) {
+	d, err := parseSwapDates(req)
+	if err != nil {
+		return swaps.Swap{}, err
+	}
+	stub, err := calendar.ParseStub(req.Stub)
+	if err != nil {
+		return swaps.Swap{}, err
+	}
+	discount, err := resolveCurve(req.DiscountCurveID, req.DiscountCurve)
+	if err != nil {
+		return swaps.Swap{}, err
+	}
+	projection, err := resolveCurve(req.ProjectionCurveID, req.ProjectionCurve)
+	if err != nil {
+		return swaps.Swap{}, err
+	}
+	if projection == nil {
+		projection = discount
+	}
+
+	if len(req.Legs) != 2 {
+		return swaps.Swap{}, errors.New("exactly two legs are required")
+	}
+	floats := 0
+	legs := make([]swaps.Leg, len(req.Legs))
+	for i, l := range req.Legs {
+		leg := swaps.Leg{
+			Type:      swaps.LegType(l.Type),
+			Pay:       l.Pay,
+			Rate:      l.Rate,
+			Spread:    l.Spread,
+			Frequency: l.Frequency,
+			Fixing:    l.Fixing,
+		}
+
+		frequency, dayCount := 1, calendar.Thirty360
+		if leg.Type == swaps.Float {
+			floats++
+			frequency, dayCount = 4, calendar.Act360
+			if leg.Projection, err = resolveCurve(l.ProjectionCurveID, l.ProjectionCurve); err != nil {
+				return swaps.Swap{}, err
+			}
+			if leg.Projection == nil {
+				leg.Projection = projection
+			}
+		}
+		if leg.Frequency == 0 {
+			leg.Frequency = frequency
+		}
+		leg.DayCount = dayCount
+		if l.DayCount != "" {
+			if leg.DayCount, err = calendar.ParseDayCount(l.DayCount); err != nil {
+				return swaps.Swap{}, err
+			}
+		}
+		legs[i] = leg
+	}
+
+	if req.Type == "irs" && floats != 1 {
+		return swaps.Swap{}, errors.New("an irs needs one fixed and one float leg")
+	}
+	if req.Type == "basis" && floats != 2 {
+		return swaps.Swap{}, errors.New("a basis swap needs two float legs")
+	}
+
+	return swaps.Swap{
+		Valuation:  d.valuation,
+		Start:      d.start,
+		End:        d.end,
+		Notional:   req.Notional,
+		Legs:       legs,
+		Calendar:   d.calendar,
+		Convention: d.convention,
+		Stub:       stub,
+		Discount:   discount,
+	}, nil
+}
+
+func buildFRA(req SwapRequest) (swaps.FRA, error) {
+	d, err := parseSwapDates(req)
+	if err != nil {
+		return swaps.FRA{}, err
+	}
+	var discount, projection curve.Curve
+	if discount, err = resolveCurve(req.DiscountCurveID, req.DiscountCurve); err != nil {
+		return swaps.FRA{}, err
+	}
+	if projection, err = resolveCurve(req.ProjectionCurveID, req.ProjectionCurve); err != nil {
+		return swaps.FRA{}, err
+	}
+	if projection == nil {
+		projection = discount
+	}
+
+	dayCount := calendar.Act360
+	if req.DayCount != "" {
+		if dayCount, err = calendar.ParseDayCount(req.DayCount); err != nil {
+			return swaps.FRA{}, err
+		}
+	}
+
+	return swaps.FRA{
+		Valuation:  d.valuation,
+		Start:      d.start,
+		End:        d.end,
+		Notional:   req.Notional,
+		Rate:       req.FixedRate,
+		Pay:        req.PayFixed,
+		DayCount:   dayCount,
+		Calendar:   d.calendar,
+		Convention: d.convention,
+		Discount:   discount,
+		Projection: projection,
+	}, nil
+}
@@ -63,3 +63,57 @@ func (h *TimeSeriesHandler) ExponentialAverage(c *gin.Context) {
 	result := timeseries.ExponentialMovingAverage(req.Data, req.Alpha)
 	h.SendSuccess(c, result)
 }
+
+const (
+	maxFuturesContracts = 500
+	maxFuturesPoints    = 200_000
+)
+
+func (h *TimeSeriesHandler) FuturesCarry(c *gin.Context) {
+	var req FuturesCarryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Quotes) == 0 || len(req.Quotes) > maxFuturesContracts {
+		h.SendError(c, http.StatusBadRequest, "between 1 and 500 quotes are required")
+		return
+	}
+
+	values, rolls, err := req.Carry.Analyze(req.Quotes)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccessWithFields(c, gin.H{
+		"contracts":   values,
+		"roll_yields": rolls,
+	})
+}
+
+func (h *TimeSeriesHandler) ContinuousFutures(c *gin.Context) {
+	var req ContinuousFuturesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	points := 0
+	for _, k := range req.Contracts {
+		points += len(k.Prices)
+	}
+	if len(req.Contracts) > maxFuturesContracts || points > maxFuturesPoints {
+		h.SendError(c, http.StatusBadRequest, "at most 500 contracts and 200000 prices are allowed")
+		return
+	}
+
+	result, err := timeseries.ContinuousFutures(req.Contracts)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
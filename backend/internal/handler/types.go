@@ -1,5 +1,19 @@
 package handler
 
+import (
+	"backend/internal/controllers/finmath"
+	"backend/internal/controllers/finmath/bonds"
+	"backend/internal/controllers/finmath/cashflow"
+	"backend/internal/controllers/finmath/credit"
+	"backend/internal/controllers/finmath/curve"
+	"backend/internal/controllers/finmath/fx"
+	"backend/internal/controllers/finmath/shortrate"
+	"backend/internal/controllers/screener"
+	"backend/internal/controllers/sim"
+	"backend/internal/controllers/timeseries"
+	"backend/internal/controllers/valuation"
+)
+
 type MatrixRequest struct {
 	MatrixA [][]float64 `json:"matrix_a"`
 	MatrixB [][]float64 `json:"matrix_b"`
@@ -34,14 +48,40 @@ type ExponentialAverageRequest struct {
 	Alpha float64   `json:"alpha"`
 }
 
+// FuturesCarryRequest values quotes by cost of carry; expiries are in years
+type FuturesCarryRequest struct {
+	timeseries.Carry
+	Quotes []timeseries.FuturesQuote `json:"quotes"`
+}
+
+type ContinuousFuturesRequest struct {
+	Contracts []timeseries.FuturesContract `json:"contracts"`
+}
+
 type OptimizationRequest struct {
-	Function string  `json:"function"`
+	Function string  `json:"function"` // Expression in x, such as "x^2 + 2*x + 1"
 	Lower    float64 `json:"lower"`
 	Upper    float64 `json:"upper"`
 	Tol      float64 `json:"tolerance"`
 }
 
+// ExpiryDates lets a request give dates instead of time_to_expiry. The valuation
+// date defaults to today and the day count to ACT/365F.
+type ExpiryDates struct {
+	ValuationDate string `json:"valuation_date"`
+	ExpiryDate    string `json:"expiry_date"`
+	DayCount      string `json:"day_count"`
+	Calendar      string `json:"calendar"`
+}
+
+// RateCurve names a stored curve whose zero rate to expiry replaces risk_free_rate
+type RateCurve struct {
+	CurveID string `json:"curve_id"`
+}
+
 type BlackScholesRequest struct {
+	ExpiryDates
+	RateCurve
 	S float64 `json:"spot_price"`
 	K float64 `json:"strike_price"`
 	T float64 `json:"time_to_expiry"`
@@ -50,6 +90,8 @@ type BlackScholesRequest struct {
 }
 
 type ImpliedVolatilityRequest struct {
+	ExpiryDates
+	RateCurve
 	S           float64 `json:"spot_price"`
 	K           float64 `json:"strike_price"`
 	T           float64 `json:"time_to_expiry"`
@@ -59,20 +101,525 @@ type ImpliedVolatilityRequest struct {
 }
 
 type DerivativeRequest struct {
-	Function string  `json:"function"`
+	Function string  `json:"function"` // Expression in x, such as "x^2 + 2*x + 1"
 	X        float64 `json:"x"`
 	H        float64 `json:"step_size"`
 }
 
 type IntegralRequest struct {
-	Function string  `json:"function"`
+	Function string  `json:"function"` // Expression in x, such as "x^2 + 2*x + 1"
 	Lower    float64 `json:"lower"`
 	Upper    float64 `json:"upper"`
 	N        int     `json:"intervals"`
 }
 
+// SimulationSeed makes a simulation reproducible: the same seed and generator
+// (pcg, xoshiro or philox) give the same draws. A random seed is used and
+// echoed when none is given.
+type SimulationSeed struct {
+	Seed      *uint64 `json:"seed"`
+	Generator string  `json:"generator"`
+}
+
+func (s SimulationSeed) streams() (*sim.Streams, error) {
+	seed := sim.RandomSeed()
+	if s.Seed != nil {
+		seed = *s.Seed
+	}
+	return sim.NewStreams(s.Generator, seed)
+}
+
 type MonteCarloRequest struct {
-	Function   string                 `json:"function"`
-	Parameters map[string]interface{} `json:"parameters"`
+	SimulationSeed
+	Function   string                 `json:"function"`   // Expression in x integrated over [lower, upper], or pi_estimation
+	Parameters map[string]interface{} `json:"parameters"` // lower and upper, defaulting to 0 and 1
 	Samples    int                    `json:"samples"`
 }
+
+type ExoticOptionRequest struct {
+	RateCurve
+	S               float64 `json:"spot_price"`
+	K               float64 `json:"strike_price"`
+	T               float64 `json:"time_to_expiry"`
+	R               float64 `json:"risk_free_rate"`
+	Q               float64 `json:"dividend_yield"`
+	V               float64 `json:"volatility"`
+	IsCall          bool    `json:"is_call"`
+	BarrierType     string  `json:"barrier_type"`
+	Barrier         float64 `json:"barrier"`
+	Rebate          float64 `json:"rebate"`
+	LowerBarrier    float64 `json:"lower_barrier"`
+	UpperBarrier    float64 `json:"upper_barrier"`
+	KnockIn         bool    `json:"knock_in"`
+	CashPayout      float64 `json:"cash_payout"`
+	AveragingStart  float64 `json:"averaging_start"`
+	ObservedExtreme float64 `json:"observed_extreme"`
+	CompoundStrike  float64 `json:"compound_strike"`
+	UnderlyingT     float64 `json:"underlying_expiry"`
+	UnderlyingCall  bool    `json:"underlying_is_call"`
+	ChoiceTime      float64 `json:"choice_time"`
+	CallStrike      float64 `json:"call_strike"`
+	PutStrike       float64 `json:"put_strike"`
+	CallExpiry      float64 `json:"call_expiry"`
+	PutExpiry       float64 `json:"put_expiry"`
+}
+
+type HestonRequest struct {
+	RateCurve
+	S      float64              `json:"spot_price"`
+	K      float64              `json:"strike_price"`
+	T      float64              `json:"time_to_expiry"`
+	R      float64              `json:"risk_free_rate"`
+	Q      float64              `json:"dividend_yield"`
+	IsCall bool                 `json:"is_call"`
+	Params finmath.HestonParams `json:"params"`
+}
+
+type VolQuote struct {
+	Strike     float64 `json:"strike"`
+	Expiry     float64 `json:"expiry"`
+	ImpliedVol float64 `json:"implied_vol"`
+}
+
+type HestonCalibrationRequest struct {
+	RateCurve
+	S       float64               `json:"spot_price"`
+	R       float64               `json:"risk_free_rate"`
+	Q       float64               `json:"dividend_yield"`
+	Quotes  []VolQuote            `json:"quotes"`
+	Initial *finmath.HestonParams `json:"initial_guess"`
+}
+
+type SABRVolRequest struct {
+	Forward   float64            `json:"forward"`
+	K         float64            `json:"strike_price"`
+	T         float64            `json:"time_to_expiry"`
+	Expansion string             `json:"expansion"`
+	Params    finmath.SABRParams `json:"params"`
+}
+
+type SABRQuote struct {
+	Strike     float64 `json:"strike"`
+	ImpliedVol float64 `json:"implied_vol"`
+	Price      float64 `json:"price"`
+	IsCall     bool    `json:"is_call"`
+}
+
+type SABRSlice struct {
+	Expiry  float64     `json:"expiry"`
+	Forward float64     `json:"forward"`
+	Quotes  []SABRQuote `json:"quotes"`
+}
+
+type SABRCalibrationRequest struct {
+	RateCurve
+	S         float64     `json:"spot_price"`
+	R         float64     `json:"risk_free_rate"`
+	Q         float64     `json:"dividend_yield"`
+	Beta      float64     `json:"beta"`
+	Expansion string      `json:"expansion"`
+	Slices    []SABRSlice `json:"slices"`
+}
+
+type SurfacePointRequest struct {
+	Strike float64 `json:"strike"`
+	Expiry float64 `json:"expiry"`
+}
+
+type VolSurfaceRequest struct {
+	RateCurve
+	S            float64               `json:"spot_price"`
+	R            float64               `json:"risk_free_rate"`
+	Q            float64               `json:"dividend_yield"`
+	Model        string                `json:"model"`
+	Quotes       []finmath.ChainQuote  `json:"quotes"`
+	Surface      *finmath.VolSurface   `json:"surface"`
+	GridStrikes  []float64             `json:"grid_strikes"`
+	GridExpiries []float64             `json:"grid_expiries"`
+	Query        []SurfacePointRequest `json:"query"`
+}
+
+type LocalVolRequest struct {
+	RateCurve
+	S         float64              `json:"spot_price"`
+	R         float64              `json:"risk_free_rate"`
+	Q         float64              `json:"dividend_yield"`
+	Model     string               `json:"model"`
+	Quotes    []finmath.ChainQuote `json:"quotes"`
+	Surface   *finmath.VolSurface  `json:"surface"`
+	Spots     []float64            `json:"spots"`
+	Times     []float64            `json:"times"`
+	MinVol    float64              `json:"min_vol"`
+	MaxVol    float64              `json:"max_vol"`
+	Smoothing *int                 `json:"smoothing"`
+}
+
+type LocalVolPriceRequest struct {
+	LocalVolRequest
+	SimulationSeed
+	T      float64            `json:"time_to_expiry"`
+	Payoff finmath.PathPayoff `json:"payoff"`
+	Steps  int                `json:"steps"`
+	Paths  int                `json:"paths"`
+}
+
+type JumpDiffusionRequest struct {
+	RateCurve
+	Model  string                    `json:"model"`
+	S      float64                   `json:"spot_price"`
+	K      float64                   `json:"strike_price"`
+	T      float64                   `json:"time_to_expiry"`
+	R      float64                   `json:"risk_free_rate"`
+	Q      float64                   `json:"dividend_yield"`
+	V      float64                   `json:"volatility"`
+	IsCall bool                      `json:"is_call"`
+	Merton *finmath.MertonJumpParams `json:"merton"`
+	Kou    *finmath.KouJumpParams    `json:"kou"`
+}
+
+type JumpPathsRequest struct {
+	SimulationSeed
+	Model  string                    `json:"model"`
+	S      float64                   `json:"spot_price"`
+	Mu     float64                   `json:"drift"`
+	V      float64                   `json:"volatility"`
+	T      float64                   `json:"time_horizon"`
+	Steps  int                       `json:"steps"`
+	Paths  int                       `json:"paths"`
+	Merton *finmath.MertonJumpParams `json:"merton"`
+	Kou    *finmath.KouJumpParams    `json:"kou"`
+}
+
+type BondRequest struct {
+	SettlementDate string  `json:"settlement_date"`
+	MaturityDate   string  `json:"maturity_date"`
+	CouponRate     float64 `json:"coupon_rate"`
+	Frequency      int     `json:"frequency"`
+	Face           float64 `json:"face_value"`
+	DayCount       string  `json:"day_count"`
+	Calendar       string  `json:"calendar"`
+}
+
+type BondPriceRequest struct {
+	BondRequest
+	Yield float64 `json:"yield"`
+}
+
+type BondYieldRequest struct {
+	BondRequest
+	Price   float64 `json:"price"`
+	IsClean *bool   `json:"is_clean_price"`
+}
+
+type BondZSpreadRequest struct {
+	BondYieldRequest
+	RateCurve
+	ZeroCurve *bonds.ZeroCurve `json:"zero_curve"`
+}
+
+type YearFractionRequest struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	DayCount  string `json:"day_count"`
+	Calendar  string `json:"calendar"`
+}
+
+type BusinessDayRequest struct {
+	Date         string `json:"date"`
+	Convention   string `json:"convention"`
+	BusinessDays int    `json:"business_days"`
+	Calendar     string `json:"calendar"`
+}
+
+type HolidaysRequest struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Calendar  string `json:"calendar"`
+}
+
+type ScheduleRequest struct {
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Frequency  int    `json:"frequency"`
+	Stub       string `json:"stub"`
+	Convention string `json:"convention"`
+	Calendar   string `json:"calendar"`
+	EndOfMonth bool   `json:"end_of_month"`
+}
+
+type LoadCalendarRequest struct {
+	Format string `json:"format"` // json or ics
+	Name   string `json:"name"`   // Used for ICS data without X-WR-CALNAME
+	Data   string `json:"data"`
+}
+
+type CurveBootstrapRequest struct {
+	Instruments   []curve.Instrument `json:"instruments"`
+	Interpolation string             `json:"interpolation"`
+	Tenors        []float64          `json:"tenors"`
+	ForwardTenor  float64            `json:"forward_tenor"`
+	Store         bool               `json:"store"`
+}
+
+type CurveQueryRequest struct {
+	Tenors       []float64 `json:"tenors"`
+	ForwardTenor float64   `json:"forward_tenor"`
+}
+
+type RateScenario struct {
+	Name      string  `json:"name"`
+	Level     float64 `json:"level"`
+	Slope     float64 `json:"slope"`
+	Curvature float64 `json:"curvature"`
+}
+
+type CurveFitRequest struct {
+	Model        string            `json:"model"`
+	ZeroRates    []curve.ZeroPoint `json:"zero_rates"`
+	Bonds        []curve.BondYield `json:"bonds"`
+	Tenors       []float64         `json:"tenors"`
+	ForwardTenor float64           `json:"forward_tenor"`
+	Scenarios    []RateScenario    `json:"scenarios"`
+	Store        bool              `json:"store"`
+}
+
+type SwapLegRequest struct {
+	Type              string               `json:"type"` // fixed or float
+	Pay               bool                 `json:"pay"`
+	Rate              float64              `json:"rate"`
+	Spread            float64              `json:"spread"`
+	Frequency         int                  `json:"frequency"`
+	DayCount          string               `json:"day_count"`
+	Fixing            *float64             `json:"fixing"`
+	ProjectionCurveID string               `json:"projection_curve_id"`
+	ProjectionCurve   *curve.DiscountCurve `json:"projection_curve"`
+}
+
+// SwapRequest prices an irs, basis swap or fra. The top-level projection curve is
+// the default for float legs and the curve an FRA fixes on.
+type SwapRequest struct {
+	Type              string               `json:"type"`
+	ValuationDate     string               `json:"valuation_date"`
+	StartDate         string               `json:"start_date"`
+	EndDate           string               `json:"end_date"`
+	Notional          float64              `json:"notional"`
+	Calendar          string               `json:"calendar"`
+	Convention        string               `json:"convention"`
+	Stub              string               `json:"stub"`
+	DiscountCurveID   string               `json:"discount_curve_id"`
+	DiscountCurve     *curve.DiscountCurve `json:"discount_curve"`
+	ProjectionCurveID string               `json:"projection_curve_id"`
+	ProjectionCurve   *curve.DiscountCurve `json:"projection_curve"`
+	Legs              []SwapLegRequest     `json:"legs"`
+	FixedRate         float64              `json:"fixed_rate"`
+	PayFixed          bool                 `json:"pay_fixed"`
+	DayCount          string               `json:"day_count"`
+}
+
+type ZeroBondOptionRequest struct {
+	Expiry       float64 `json:"expiry"`
+	BondMaturity float64 `json:"bond_maturity"`
+	Strike       float64 `json:"strike"`
+	IsCall       bool    `json:"is_call"`
+	American     bool    `json:"american"`   // Tree only
+	TreeSteps    int     `json:"tree_steps"` // Hull-White only; 0 skips the tree
+}
+
+type ShortRateCapRequest struct {
+	Maturity  float64 `json:"maturity"`
+	Strike    float64 `json:"strike"`
+	Frequency int     `json:"frequency"`
+}
+
+type ShortRateSwaptionRequest struct {
+	Expiry    float64 `json:"expiry"`
+	Tenor     float64 `json:"tenor"`
+	Strike    float64 `json:"strike"`
+	Frequency int     `json:"frequency"`
+	IsPayer   bool    `json:"is_payer"`
+}
+
+// ShortRateRequest takes its initial curve from curve_id or curve; only
+// Hull-White uses it
+type ShortRateRequest struct {
+	Model      string                    `json:"model"`
+	Params     shortrate.Params          `json:"params"`
+	CurveID    string                    `json:"curve_id"`
+	Curve      *curve.DiscountCurve      `json:"curve"`
+	Maturities []float64                 `json:"maturities"`
+	BondOption *ZeroBondOptionRequest    `json:"bond_option"`
+	Cap        *ShortRateCapRequest      `json:"cap"`
+	Swaption   *ShortRateSwaptionRequest `json:"swaption"`
+}
+
+type ShortRateCalibrationRequest struct {
+	Model   string               `json:"model"`
+	CurveID string               `json:"curve_id"`
+	Curve   *curve.DiscountCurve `json:"curve"`
+	Quotes  []shortrate.Quote    `json:"quotes"`
+	Initial *shortrate.Params    `json:"initial"`
+}
+
+type ShortRatePathsRequest struct {
+	SimulationSeed
+	Model   string               `json:"model"`
+	Params  shortrate.Params     `json:"params"`
+	CurveID string               `json:"curve_id"`
+	Curve   *curve.DiscountCurve `json:"curve"`
+	T       float64              `json:"time_horizon"`
+	Steps   int                  `json:"steps"`
+	Paths   int                  `json:"paths"`
+}
+
+// RateOptionVol sets either a flat vol or a term structure; price, when set,
+// asks for the flat implied vol instead
+type RateOptionVol struct {
+	Vol              float64          `json:"vol"`
+	VolTermStructure *finmath.RateVol `json:"vol_term_structure"`
+	MarketPrice      *float64         `json:"price"`
+}
+
+type CapFloorRequest struct {
+	finmath.CapFloor
+	RateOptionVol
+	CurveID string               `json:"curve_id"`
+	Curve   *curve.DiscountCurve `json:"curve"`
+}
+
+type SwaptionRequest struct {
+	finmath.EuropeanSwaption
+	RateOptionVol
+	CurveID string               `json:"curve_id"`
+	Curve   *curve.DiscountCurve `json:"curve"`
+}
+
+// CashflowRequest takes periodic amounts, or dated ones when dates is set. Rates
+// are per period for periodic flows and annual for dated ones. Frequency converts
+// periods to years for curve discounting.
+// CashflowRequest places amounts one period apart, where a period is a year over
+// Frequency, or on Dates. Rate, FinanceRate and ReinvestRate compound once per
+// period, or per year for dated flows, while the curve is always read in years.
+type CashflowRequest struct {
+	Amounts      []float64            `json:"amounts"`
+	Dates        []string             `json:"dates"`
+	Frequency    int                  `json:"frequency"`
+	Rate         *float64             `json:"rate"`
+	CurveID      string               `json:"curve_id"`
+	Curve        *curve.DiscountCurve `json:"curve"`
+	FinanceRate  *float64             `json:"finance_rate"`
+	ReinvestRate *float64             `json:"reinvest_rate"`
+	Annuity      *cashflow.Annuity    `json:"annuity"`
+}
+
+// AmortizationRequest prices the schedule at a nominal annual discount rate and
+// solves for the yield given a price as a percentage of principal
+type AmortizationRequest struct {
+	cashflow.Loan
+	DiscountRate *float64 `json:"discount_rate"`
+	Price        *float64 `json:"price"`
+}
+
+// CDSRequest bootstraps a hazard curve from quotes unless hazard_curve is given,
+// and prices cds against it. Discounting uses curve_id, curve or a flat rate.
+type CDSRequest struct {
+	Quotes      []credit.CDSQuote    `json:"quotes"`
+	HazardCurve *credit.HazardCurve  `json:"hazard_curve"`
+	Recovery    *float64             `json:"recovery"`
+	Frequency   int                  `json:"frequency"`
+	CurveID     string               `json:"curve_id"`
+	Curve       *curve.DiscountCurve `json:"curve"`
+	Rate        float64              `json:"rate"`
+	CDS         *credit.CDS          `json:"cds"`
+	Tenors      []float64            `json:"tenors"`
+}
+
+// StrategyRequest defaults the spot grid to 101 points from half the lowest to
+// 1.5 times the highest of spot and strikes
+type StrategyRequest struct {
+	RateCurve
+	finmath.Strategy
+	MinSpot float64 `json:"min_spot"`
+	MaxSpot float64 `json:"max_spot"`
+	Points  int     `json:"points"`
+}
+
+type ChainArbitrageRequest struct {
+	RateCurve
+	S         float64               `json:"spot_price"`
+	R         float64               `json:"risk_free_rate"`
+	Q         float64               `json:"dividend_yield"`
+	Options   []finmath.ChainOption `json:"options"`
+	MinProfit float64               `json:"min_profit"`
+}
+
+// FXForwardRequest quotes pairs as domestic units per foreign unit. Tenors add a
+// forward points strip, and forward backs out the implied foreign rate.
+type FXForwardRequest struct {
+	Spot          float64        `json:"spot_price"`
+	DomesticRate  float64        `json:"domestic_rate"`
+	ForeignRate   float64        `json:"foreign_rate"`
+	T             float64        `json:"time_to_maturity"`
+	Tenors        []float64      `json:"tenors"`
+	PipSize       float64        `json:"pip_size"`
+	Compounding   fx.Compounding `json:"compounding"`
+	MarketForward *float64       `json:"forward"`
+}
+
+// FXCrossRequest derives pairs, such as "EUR/JPY", from quotes; when pairs is
+// empty every pair without a direct quote is derived
+type FXCrossRequest struct {
+	Quotes    []fx.Quote `json:"quotes"`
+	Pairs     []string   `json:"pairs"`
+	MinProfit float64    `json:"min_profit"`
+}
+
+// FXOptionRequest prices strike when given and inverts delta, quoted under
+// delta_convention (default spot), to a strike
+type FXOptionRequest struct {
+	Spot         float64            `json:"spot_price"`
+	Strike       float64            `json:"strike"`
+	T            float64            `json:"time_to_maturity"`
+	DomesticRate float64            `json:"domestic_rate"`
+	ForeignRate  float64            `json:"foreign_rate"`
+	Volatility   float64            `json:"volatility"`
+	IsCall       bool               `json:"is_call"`
+	Delta        *float64           `json:"delta"`
+	Convention   fx.DeltaConvention `json:"delta_convention"`
+}
+
+// DCFRequest takes wacc directly or builds it from capm. The sensitivity grid
+// steps WACC by 1% and growth by 0.5%, or the exit multiple by 1x, unless set.
+type DCFRequest struct {
+	valuation.DCF
+	WACC             *float64        `json:"wacc"` // Shadows DCF.WACC so a 0% wacc is told apart from a missing one
+	CAPM             *valuation.CAPM `json:"capm"`
+	SensitivitySteps *int            `json:"sensitivity_steps"`
+	WACCStep         float64         `json:"wacc_step"`
+	TerminalStep     float64         `json:"terminal_step"`
+}
+
+// RatiosRequest takes statements for one or more periods, oldest first
+type RatiosRequest struct {
+	Periods []valuation.Period `json:"periods"`
+}
+
+// ScoresRequest takes statements oldest first. price_level deflates total
+// assets, in millions, for the Ohlson size term and defaults to 1.
+type ScoresRequest struct {
+	Periods     []valuation.Period    `json:"periods"`
+	AltmanModel valuation.AltmanModel `json:"altman_model"`
+	PriceLevel  float64               `json:"price_level"`
+}
+
+type LoadUniverseRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"` // csv or json
+	Data   string `json:"data"`
+}
+
+// ScreenRequest screens a stored universe by name, or inline securities
+type ScreenRequest struct {
+	screener.Screen
+	Universe   string              `json:"universe"`
+	Securities []screener.Security `json:"securities"`
+}
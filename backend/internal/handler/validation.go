@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"backend/internal/controllers/expr"
 	"fmt"
 	"math"
 	"strings"
@@ -16,20 +17,25 @@ func (v *Validator) IsValidFloat(f float64) bool {
 	return !math.IsNaN(f) && !math.IsInf(f, 0)
 }
 
-func (v *Validator) IsValidFunction(fn string) bool {
-	fn = strings.TrimSpace(fn)
-	if fn == "" {
-		return false
+// ParseFunction compiles a function of x such as "x^2 + 2*x + 1". Endpoints used
+// to take the name "quadratic" and evaluate a fixed example, which legacy gives,
+// so older clients keep their results.
+func (v *Validator) ParseFunction(fn, legacy string) (func(float64) float64, error) {
+	if legacy != "" && strings.EqualFold(strings.TrimSpace(fn), "quadratic") {
+		fn = legacy
 	}
-	// Block obvious dangerous patterns
-	dangerous := []string{"import", "os", "exec", "eval", "system"}
-	lower := strings.ToLower(fn)
-	for _, d := range dangerous {
-		if strings.Contains(lower, d) {
-			return false
+	e, err := expr.Parse(fn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid function: %w", err)
+	}
+	f, err := e.Func("x")
+	if err != nil {
+		if vars := e.Variables(); len(vars) == 1 && strings.TrimSpace(fn) == vars[0] {
+			return nil, fmt.Errorf("function %q is not an expression in x; named functions were replaced by expressions such as \"x^2 + 2*x + 1\"", vars[0])
 		}
+		return nil, fmt.Errorf("invalid function: %w", err)
 	}
-	return true
+	return func(x float64) float64 { return f(x) }, nil
 }
 
 func (v *Validator) ValidateMatrix(matrix [][]float64) error {
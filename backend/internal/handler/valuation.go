@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"backend/internal/controllers/valuation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ValuationHandler struct {
+	*BaseHandler
+}
+
+func NewValuationHandler() *ValuationHandler {
+	return &ValuationHandler{
+		BaseHandler: NewBaseHandler(),
+	}
+}
+
+func (h *ValuationHandler) DCF(c *gin.Context) {
+	var req DCFRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := gin.H{}
+	if req.CAPM != nil {
+		wacc, err := req.CAPM.WACC(req.TaxRate)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.DCF.WACC = wacc
+		response["cost_of_equity"] = req.CAPM.CostOfEquity()
+	} else if req.WACC != nil {
+		req.DCF.WACC = *req.WACC
+	} else {
+		h.SendError(c, http.StatusBadRequest, "wacc or capm is required")
+		return
+	}
+
+	result, err := req.DCF.Value()
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	steps := 2
+	if req.SensitivitySteps != nil {
+		steps = *req.SensitivitySteps
+	}
+	if req.WACCStep == 0 {
+		req.WACCStep = 0.01
+	}
+	if req.TerminalStep == 0 {
+		req.TerminalStep = 0.005
+		if req.ExitMultiple > 0 {
+			req.TerminalStep = 1
+		}
+	}
+	sensitivity, err := req.DCF.Sensitivity(steps, req.WACCStep, req.TerminalStep)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response["wacc"] = req.DCF.WACC
+	response["valuation"] = result
+	response["sensitivity"] = sensitivity
+	h.SendSuccessWithFields(c, response)
+}
+
+func (h *ValuationHandler) Ratios(c *gin.Context) {
+	var req RatiosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Periods) > 200 {
+		h.SendError(c, http.StatusBadRequest, "at most 200 periods are allowed")
+		return
+	}
+
+	result, err := valuation.Ratios(req.Periods)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
+
+func (h *ValuationHandler) Scores(c *gin.Context) {
+	var req ScoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Periods) > 200 {
+		h.SendError(c, http.StatusBadRequest, "at most 200 periods are allowed")
+		return
+	}
+
+	result, err := valuation.Scores(req.Periods, req.AltmanModel, req.PriceLevel)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.SendSuccess(c, result)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"backend/internal/controllers/finmath"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxSurfaceQuotes  = 5000
+	maxSurfaceQueries = 10_000
+	maxGridAxis       = 1000
+	maxGridPoints     = 100_000
+)
+
+// validateSurfaceGrid bounds each axis before the product, so the product
+// cannot overflow
+func validateSurfaceGrid(strikes, expiries []float64) error {
+	if len(strikes) > maxGridAxis || len(expiries) > maxGridAxis {
+		return errors.New("grids are limited to 1000 points on each axis")
+	}
+	if len(strikes)*len(expiries) > maxGridPoints {
+		return errors.New("grids are limited to 100,000 points")
+	}
+	return nil
+}
+
+// VolSurface fits a surface to an option chain, or evaluates a previously fitted
+// surface when one is passed back in place of the quotes.
+func (h *FinMathHandler) VolSurface(c *gin.Context) {
+	var req VolSurfaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Quotes) > maxSurfaceQuotes {
+		h.SendError(c, http.StatusBadRequest, "at most 5000 quotes can be fitted")
+		return
+	}
+	if len(req.Query) > maxSurfaceQueries {
+		h.SendError(c, http.StatusBadRequest, "at most 10,000 query points can be evaluated")
+		return
+	}
+
+	rates, err := req.termRates(req.R)
+	if err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := gin.H{}
+	var surface finmath.VolSurface
+
+	if req.Surface != nil {
+		if err := req.Surface.Validate(); err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		surface = *req.Surface
+		if req.CurveID != "" {
+			surface.SetRates(rates)
+		}
+		response["surface"] = surface
+	} else {
+		if len(req.Quotes) == 0 {
+			h.SendError(c, http.StatusBadRequest, "quotes or a fitted surface are required")
+			return
+		}
+
+		fit, err := finmath.BuildVolSurface(req.S, rates, req.Q, finmath.SurfaceModel(req.Model), req.Quotes)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		surface = fit.Surface
+		response["surface"] = fit.Surface
+		response["rmse"] = fit.RMSE
+		response["quotes"] = fit.Quotes
+		response["rejected"] = fit.Rejected
+		response["arbitrage"] = fit.Arbitrage
+
+		if len(req.GridStrikes) == 0 {
+			req.GridStrikes = defaultStrikeGrid(req.Quotes, 21)
+		}
+	}
+
+	if len(req.GridExpiries) == 0 {
+		for _, sl := range surface.Slices {
+			req.GridExpiries = append(req.GridExpiries, sl.Expiry)
+		}
+	}
+
+	if err := validateSurfaceGrid(req.GridStrikes, req.GridExpiries); err != nil {
+		h.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.GridStrikes) > 0 {
+		vols, err := surface.Grid(req.GridStrikes, req.GridExpiries)
+		if err != nil {
+			h.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response["grid"] = gin.H{
+			"strikes":  req.GridStrikes,
+			"expiries": req.GridExpiries,
+			"vols":     vols,
+		}
+	}
+
+	if len(req.Query) > 0 {
+		results := make([]gin.H, len(req.Query))
+		for i, p := range req.Query {
+			vol, err := surface.ImpliedVol(p.Strike, p.Expiry)
+			if err != nil {
+				h.SendError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			results[i] = gin.H{"strike": p.Strike, "expiry": p.Expiry, "vol": vol}
+		}
+		response["query"] = results
+	}
+
+	h.SendSuccessWithFields(c, response)
+}
+
+func defaultStrikeGrid(quotes []finmath.ChainQuote, points int) []float64 {
+	var strikes []float64
+	for _, q := range quotes {
+		if q.Strike > 0 {
+			strikes = append(strikes, q.Strike)
+		}
+	}
+	if len(strikes) == 0 {
+		return nil
+	}
+	sort.Float64s(strikes)
+
+	lo, hi := strikes[0], strikes[len(strikes)-1]
+	grid := make([]float64, points)
+	for i := range grid {
+		grid[i] = lo + (hi-lo)*float64(i)/float64(points-1)
+	}
+	return grid
+}
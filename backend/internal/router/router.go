@@ -51,6 +51,8 @@ func SetupRouter() *gin.Engine {
 	finMathHandler := handler.NewFinMathHandler()
 	calculusHandler := handler.NewCalculusHandler()
 	simHandler := handler.NewSimulationHandler()
+	valuationHandler := handler.NewValuationHandler()
+	screenerHandler := handler.NewScreenerHandler()
 	financeNewsHandler := handler.NewFinanceNewsHandler()
 
 	// API routes
@@ -85,6 +87,8 @@ func SetupRouter() *gin.Engine {
 		{
 			timeseries.POST("/moving-average", timeSeriesHandler.MovingAverage)
 			timeseries.POST("/exponential-average", timeSeriesHandler.ExponentialAverage)
+			timeseries.POST("/futures/carry", timeSeriesHandler.FuturesCarry)
+			timeseries.POST("/futures/continuous", timeSeriesHandler.ContinuousFutures)
 		}
 
 		// Optimization routes
@@ -98,6 +102,41 @@ func SetupRouter() *gin.Engine {
 		{
 			finmath.POST("/black-scholes", finMathHandler.BlackScholes)
 			finmath.POST("/implied-volatility", finMathHandler.ImpliedVolatility)
+			finmath.POST("/exotic/:type", finMathHandler.ExoticOption)
+			finmath.POST("/heston", finMathHandler.HestonPrice)
+			finmath.POST("/heston/calibrate", finMathHandler.HestonCalibrate)
+			finmath.POST("/sabr", finMathHandler.SABRVol)
+			finmath.POST("/sabr/calibrate", finMathHandler.SABRCalibrate)
+			finmath.POST("/vol-surface", finMathHandler.VolSurface)
+			finmath.POST("/local-vol", finMathHandler.LocalVol)
+			finmath.POST("/local-vol/price", finMathHandler.LocalVolPrice)
+			finmath.POST("/jump-diffusion", finMathHandler.JumpDiffusion)
+			finmath.POST("/bond/price", finMathHandler.BondPrice)
+			finmath.POST("/bond/yield", finMathHandler.BondYield)
+			finmath.POST("/bond/z-spread", finMathHandler.BondZSpread)
+			finmath.POST("/calendar/year-fraction", finMathHandler.YearFraction)
+			finmath.POST("/calendar/adjust", finMathHandler.AdjustDate)
+			finmath.POST("/calendar/holidays", finMathHandler.Holidays)
+			finmath.POST("/calendar/schedule", finMathHandler.Schedule)
+			finmath.POST("/calendar/load", finMathHandler.LoadCalendar)
+			finmath.POST("/curve/bootstrap", finMathHandler.CurveBootstrap)
+			finmath.POST("/curve/fit", finMathHandler.CurveFit)
+			finmath.POST("/curve/:id", finMathHandler.CurveQuery)
+			finmath.DELETE("/curve/:id", finMathHandler.CurveDelete)
+			finmath.POST("/swap", finMathHandler.Swap)
+			finmath.POST("/short-rate", finMathHandler.ShortRate)
+			finmath.POST("/short-rate/calibrate", finMathHandler.ShortRateCalibrate)
+			finmath.POST("/cap-floor", finMathHandler.CapFloor)
+			finmath.POST("/swaption", finMathHandler.Swaption)
+			finmath.POST("/cashflow", finMathHandler.Cashflow)
+			finmath.POST("/amortization", finMathHandler.Amortization)
+			finmath.POST("/credit/cds", finMathHandler.CDS)
+			finmath.POST("/credit/merton", finMathHandler.Merton)
+			finmath.POST("/strategy", finMathHandler.Strategy)
+			finmath.POST("/chain-arbitrage", finMathHandler.ChainArbitrage)
+			finmath.POST("/fx/forward", finMathHandler.FXForward)
+			finmath.POST("/fx/cross-rates", finMathHandler.FXCrossRates)
+			finmath.POST("/fx/option", finMathHandler.FXOption)
 		}
 
 		// Calculus routes
@@ -111,6 +150,25 @@ func SetupRouter() *gin.Engine {
 		sim := api.Group("/sim")
 		{
 			sim.POST("/monte-carlo", simHandler.MonteCarlo)
+			sim.POST("/jump-diffusion", simHandler.JumpDiffusionPaths)
+			sim.POST("/short-rate", simHandler.ShortRatePaths)
+		}
+
+		// Valuation routes
+		valuation := api.Group("/valuation")
+		{
+			valuation.POST("/dcf", valuationHandler.DCF)
+			valuation.POST("/ratios", valuationHandler.Ratios)
+			valuation.POST("/scores", valuationHandler.Scores)
+		}
+
+		// Screener routes
+		screener := api.Group("/screener")
+		{
+			screener.POST("/universe", screenerHandler.LoadUniverse)
+			screener.GET("/universes", screenerHandler.ListUniverses)
+			screener.DELETE("/universe/:name", screenerHandler.DeleteUniverse)
+			screener.POST("/screen", screenerHandler.Screen)
 		}
 
 		finance := api.Group("/finance")
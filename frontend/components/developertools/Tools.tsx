@@ -641,7 +641,7 @@ const Tools: React.FC = () => {
                       className="w-full p-3 bg-white/5 border border-blue-500/30 rounded-lg text-white"
                       id="optFunction"
                     >
-                      <option value="quadratic">Quadratic Function</option>
+                      <option value="(x-2)^2">Quadratic Function (x-2)^2</option>
                     </select>
                   </div>
                   <div className="grid grid-cols-3 gap-4">